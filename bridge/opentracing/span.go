@@ -0,0 +1,165 @@
+package opentracing
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Vinelab/tracing-go"
+	goopentracing "github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/log"
+)
+
+// SpanContext exposes tracing.SpanContext as opentracing.SpanContext. Baggage is available
+// with drivers supporting it (i.e. Zipkin).
+type SpanContext struct {
+	tracing.SpanContext
+}
+
+// baggageContext is implemented by span contexts of drivers supporting baggage
+type baggageContext interface {
+	Baggage() map[string]string
+}
+
+// baggageSpan is implemented by spans of drivers supporting baggage
+type baggageSpan interface {
+	SetBaggageItem(key string, value string)
+	BaggageItem(key string) string
+}
+
+// ForeachBaggageItem calls the handler for every baggage item until it returns false
+func (spanCtx SpanContext) ForeachBaggageItem(handler func(k, v string) bool) {
+	withBaggage, ok := spanCtx.SpanContext.(baggageContext)
+	if !ok {
+		return
+	}
+
+	for key, value := range withBaggage.Baggage() {
+		if !handler(key, value) {
+			return
+		}
+	}
+}
+
+// Span exposes tracing.Span as opentracing.Span
+type Span struct {
+	span   tracing.Span
+	tracer *Tracer
+}
+
+// Unwrap returns the underlying span
+func (span *Span) Unwrap() tracing.Span {
+	return span.span
+}
+
+// Finish notifies that operation has finished
+func (span *Span) Finish() {
+	span.span.Finish()
+}
+
+// FinishWithOptions records given logs and notifies that operation has finished at the given time
+func (span *Span) FinishWithOptions(opts goopentracing.FinishOptions) {
+	for _, record := range opts.LogRecords {
+		span.logAt(record.Timestamp, record.Fields)
+	}
+
+	for _, data := range opts.BulkLogData {
+		record := data.ToLogRecord()
+		span.logAt(record.Timestamp, record.Fields)
+	}
+
+	if opts.FinishTime.IsZero() {
+		span.span.Finish()
+	} else {
+		span.span.FinishAt(opts.FinishTime)
+	}
+}
+
+// Context retrieves SpanContext for this Span
+func (span *Span) Context() goopentracing.SpanContext {
+	return SpanContext{SpanContext: span.span.Context()}
+}
+
+// SetOperationName sets (overrides) the name of the span
+func (span *Span) SetOperationName(operationName string) goopentracing.Span {
+	span.span.SetName(operationName)
+	return span
+}
+
+// SetTag records the tag with value converted into string
+func (span *Span) SetTag(key string, value interface{}) goopentracing.Span {
+	span.span.Tag(key, fmt.Sprint(value))
+	return span
+}
+
+// LogFields records structured data
+func (span *Span) LogFields(fields ...log.Field) {
+	span.logAt(time.Time{}, fields)
+}
+
+// LogKV records alternating key-value pairs
+func (span *Span) LogKV(alternatingKeyValues ...interface{}) {
+	fields, err := log.InterleavedKVToFields(alternatingKeyValues...)
+	if err != nil {
+		span.LogFields(log.Error(err), log.String("function", "LogKV"))
+		return
+	}
+
+	span.LogFields(fields...)
+}
+
+// SetBaggageItem sets the baggage item propagated to child spans, it does nothing
+// with drivers not supporting baggage
+func (span *Span) SetBaggageItem(restrictedKey, value string) goopentracing.Span {
+	if withBaggage, ok := span.span.(baggageSpan); ok {
+		withBaggage.SetBaggageItem(restrictedKey, value)
+	}
+
+	return span
+}
+
+// BaggageItem returns the value of the baggage item or empty string if there is none
+func (span *Span) BaggageItem(restrictedKey string) string {
+	if withBaggage, ok := span.span.(baggageSpan); ok {
+		return withBaggage.BaggageItem(restrictedKey)
+	}
+
+	return ""
+}
+
+// Tracer returns the Tracer that created this Span
+func (span *Span) Tracer() goopentracing.Tracer {
+	return span.tracer
+}
+
+// LogEvent is deprecated, use LogFields or LogKV
+func (span *Span) LogEvent(event string) {
+	span.Log(goopentracing.LogData{Event: event})
+}
+
+// LogEventWithPayload is deprecated, use LogFields or LogKV
+func (span *Span) LogEventWithPayload(event string, payload interface{}) {
+	span.Log(goopentracing.LogData{Event: event, Payload: payload})
+}
+
+// Log is deprecated, use LogFields or LogKV
+func (span *Span) Log(data goopentracing.LogData) {
+	record := data.ToLogRecord()
+	span.logAt(record.Timestamp, record.Fields)
+}
+
+func (span *Span) logAt(timestamp time.Time, fields []log.Field) {
+	if len(fields) == 0 {
+		return
+	}
+
+	data := make(map[string]string, len(fields))
+	for _, field := range fields {
+		data[field.Key()] = fmt.Sprint(field.Value())
+	}
+
+	if timestamp.IsZero() {
+		span.span.Log(data)
+	} else {
+		span.span.LogAt(timestamp, data)
+	}
+}
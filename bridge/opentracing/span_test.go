@@ -0,0 +1,55 @@
+package opentracing
+
+import (
+	"errors"
+	"testing"
+
+	goopentracing "github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/log"
+)
+
+func TestLogFields(t *testing.T) {
+	bridge, _, rec := newTestTracer(t)
+
+	span := bridge.StartSpan("Request")
+	span.LogFields(log.String("event", "cache miss"), log.Int("attempt", 2), log.Error(errors.New("timeout")))
+	span.LogKV("event", "retry")
+	span.Finish()
+
+	recorded := findSpan(t, rec.Flush(), "Request")
+	if len(recorded.Annotations) != 2 {
+		t.Fatalf("expected 2 annotations, got %v", recorded.Annotations)
+	}
+
+	expected := []string{`attempt=2 error.object=timeout event="cache miss"`, "event=retry"}
+	for i, value := range expected {
+		if recorded.Annotations[i].Value != value {
+			t.Errorf("expected %q, got %q", value, recorded.Annotations[i].Value)
+		}
+	}
+}
+
+func TestBaggage(t *testing.T) {
+	bridge, _, _ := newTestTracer(t)
+
+	parent := bridge.StartSpan("Request")
+	parent.SetBaggageItem("tenant", "acme")
+
+	if parent.BaggageItem("tenant") != "acme" {
+		t.Errorf("expected baggage item, got %q", parent.BaggageItem("tenant"))
+	}
+
+	child := bridge.StartSpan("Query", goopentracing.ChildOf(parent.Context()))
+	if child.BaggageItem("tenant") != "acme" {
+		t.Errorf("expected baggage inherited by child, got %q", child.BaggageItem("tenant"))
+	}
+
+	items := map[string]string{}
+	child.Context().ForeachBaggageItem(func(k, v string) bool {
+		items[k] = v
+		return true
+	})
+	if len(items) != 1 || items["tenant"] != "acme" {
+		t.Errorf("expected baggage of the context, got %v", items)
+	}
+}
@@ -0,0 +1,196 @@
+package opentracing
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/Vinelab/tracing-go"
+	"github.com/Vinelab/tracing-go/formats"
+	goopentracing "github.com/opentracing/opentracing-go"
+)
+
+// Tracer exposes tracing.Tracer as opentracing.Tracer, so that spans created by libraries
+// instrumented with opentracing-go land in the same traces. It should be initialized using NewTracer method.
+type Tracer struct {
+	tracer tracing.Tracer
+	opt    TracerOptions
+}
+
+// TracerOptions is a configuration container to setup the Tracer.
+type TracerOptions struct {
+	// TextMapFormat is the format descriptor opentracing.TextMap and opentracing.Binary formats are mapped onto
+	// Defaults to formats.TextMap
+	TextMapFormat string
+	// HTTPHeadersFormat is the format descriptor opentracing.HTTPHeaders format is mapped onto
+	// Defaults to formats.HTTP
+	HTTPHeadersFormat string
+}
+
+// NewTracer returns a new Tracer
+func NewTracer(tracer tracing.Tracer, opt TracerOptions) *Tracer {
+	if opt.TextMapFormat == "" {
+		opt.TextMapFormat = formats.TextMap
+	}
+	if opt.HTTPHeadersFormat == "" {
+		opt.HTTPHeadersFormat = formats.HTTP
+	}
+
+	return &Tracer{tracer: tracer, opt: opt}
+}
+
+// StartSpan starts a new span. The first ChildOf reference becomes the parent, FollowsFrom references
+// and the rest of ChildOf references are recorded as references of the span.
+//
+// Without references, the span starts a new trace as required by OpenTracing.
+func (tracer *Tracer) StartSpan(operationName string, opts ...goopentracing.StartSpanOption) goopentracing.Span {
+	options := goopentracing.StartSpanOptions{}
+	for _, opt := range opts {
+		opt.Apply(&options)
+	}
+
+	var parent tracing.SpanContext
+	startOpts := make([]tracing.StartSpanOption, 0, len(options.References)+1)
+
+	if !options.StartTime.IsZero() {
+		startOpts = append(startOpts, tracing.StartTime(options.StartTime))
+	}
+
+	for _, ref := range options.References {
+		spanCtx, ok := ref.ReferencedContext.(SpanContext)
+		if !ok {
+			continue
+		}
+
+		switch {
+		case ref.Type == goopentracing.ChildOfRef && parent == nil:
+			parent = spanCtx.SpanContext
+		case ref.Type == goopentracing.FollowsFromRef:
+			startOpts = append(startOpts, tracing.FollowsFrom(spanCtx.SpanContext, nil))
+		default:
+			startOpts = append(startOpts, tracing.Link(spanCtx.SpanContext, nil))
+		}
+	}
+
+	if parent == nil {
+		parent = tracer.tracer.EmptySpanContext()
+	}
+
+	span := tracer.tracer.StartSpan(operationName, parent, startOpts...)
+	for key, value := range options.Tags {
+		span.Tag(key, fmt.Sprint(value))
+	}
+
+	return &Span{span: span, tracer: tracer}
+}
+
+// Inject serializes span context into the carrier using formats registered on the underlying tracer
+func (tracer *Tracer) Inject(sm goopentracing.SpanContext, format interface{}, carrier interface{}) error {
+	spanCtx, ok := sm.(SpanContext)
+	if !ok {
+		return goopentracing.ErrInvalidSpanContext
+	}
+
+	switch format {
+	case goopentracing.TextMap:
+		writer, ok := carrier.(goopentracing.TextMapWriter)
+		if !ok {
+			return goopentracing.ErrInvalidCarrier
+		}
+
+		textMap := make(map[string]string)
+		if err := tracer.tracer.InjectContext(&textMap, tracer.opt.TextMapFormat, spanCtx.SpanContext); err != nil {
+			return err
+		}
+
+		for key, value := range textMap {
+			writer.Set(key, value)
+		}
+
+		return nil
+	case goopentracing.HTTPHeaders:
+		header, ok := carrier.(goopentracing.HTTPHeadersCarrier)
+		if !ok {
+			return goopentracing.ErrInvalidCarrier
+		}
+
+		// Injected headers end up in the carrier since the request shares its map
+		req := &http.Request{Header: http.Header(header)}
+
+		return tracer.tracer.InjectContext(req, tracer.opt.HTTPHeadersFormat, spanCtx.SpanContext)
+	case goopentracing.Binary:
+		writer, ok := carrier.(io.Writer)
+		if !ok {
+			return goopentracing.ErrInvalidCarrier
+		}
+
+		textMap := make(map[string]string)
+		if err := tracer.tracer.InjectContext(&textMap, tracer.opt.TextMapFormat, spanCtx.SpanContext); err != nil {
+			return err
+		}
+
+		return json.NewEncoder(writer).Encode(textMap)
+	}
+
+	return goopentracing.ErrUnsupportedFormat
+}
+
+// Extract deserializes span context from the carrier using formats registered on the underlying tracer
+func (tracer *Tracer) Extract(format interface{}, carrier interface{}) (goopentracing.SpanContext, error) {
+	var (
+		spanCtx tracing.SpanContext
+		err     error
+	)
+
+	switch format {
+	case goopentracing.TextMap:
+		reader, ok := carrier.(goopentracing.TextMapReader)
+		if !ok {
+			return nil, goopentracing.ErrInvalidCarrier
+		}
+
+		textMap := make(map[string]string)
+		err = reader.ForeachKey(func(key, value string) error {
+			textMap[strings.ToLower(key)] = value
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		spanCtx, err = tracer.tracer.Extract(textMap, tracer.opt.TextMapFormat)
+	case goopentracing.HTTPHeaders:
+		header, ok := carrier.(goopentracing.HTTPHeadersCarrier)
+		if !ok {
+			return nil, goopentracing.ErrInvalidCarrier
+		}
+
+		spanCtx, err = tracer.tracer.Extract(&http.Request{Header: http.Header(header)}, tracer.opt.HTTPHeadersFormat)
+	case goopentracing.Binary:
+		reader, ok := carrier.(io.Reader)
+		if !ok {
+			return nil, goopentracing.ErrInvalidCarrier
+		}
+
+		textMap := make(map[string]string)
+		if err := json.NewDecoder(reader).Decode(&textMap); err != nil {
+			return nil, goopentracing.ErrSpanContextCorrupted
+		}
+
+		spanCtx, err = tracer.tracer.Extract(textMap, tracer.opt.TextMapFormat)
+	default:
+		return nil, goopentracing.ErrUnsupportedFormat
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !spanCtx.IsValid() {
+		return nil, goopentracing.ErrSpanContextNotFound
+	}
+
+	return SpanContext{SpanContext: spanCtx}, nil
+}
@@ -0,0 +1,163 @@
+package opentracing
+
+import (
+	"bytes"
+	"net/http"
+	"testing"
+
+	"github.com/Vinelab/tracing-go/drivers/zipkin"
+	goopentracing "github.com/opentracing/opentracing-go"
+	"github.com/openzipkin/zipkin-go/model"
+	"github.com/openzipkin/zipkin-go/reporter/recorder"
+)
+
+func newTestTracer(t *testing.T) (*Tracer, *zipkin.Tracer, *recorder.ReporterRecorder) {
+	t.Helper()
+
+	rec := recorder.NewReporter()
+	tracer, err := zipkin.NewTracer(zipkin.TracerOptions{ServiceName: "opentracing-test", Reporter: rec})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return NewTracer(tracer, TracerOptions{}), tracer, rec
+}
+
+func findSpan(t *testing.T, spans []model.SpanModel, name string) model.SpanModel {
+	t.Helper()
+
+	for _, span := range spans {
+		if span.Name == name {
+			return span
+		}
+	}
+
+	t.Fatalf("span %q not recorded", name)
+	return model.SpanModel{}
+}
+
+func TestStartSpanWithoutReferencesStartsTrace(t *testing.T) {
+	bridge, tracer, rec := newTestTracer(t)
+
+	current := tracer.StartSpan("Request", tracer.EmptySpanContext())
+	span := bridge.StartSpan("Query")
+	span.Finish()
+	current.Finish()
+
+	recorded := findSpan(t, rec.Flush(), "Query")
+	if recorded.TraceID.String() == current.Context().TraceID() || recorded.ParentID != nil {
+		t.Errorf("expected a new trace, got trace %s with parent %v", recorded.TraceID, recorded.ParentID)
+	}
+}
+
+func TestStartSpanReferences(t *testing.T) {
+	bridge, _, rec := newTestTracer(t)
+
+	parent := bridge.StartSpan("Parent")
+	other := bridge.StartSpan("Other")
+	previous := bridge.StartSpan("Previous")
+
+	span := bridge.StartSpan("Child",
+		goopentracing.ChildOf(parent.Context()),
+		goopentracing.ChildOf(other.Context()),
+		goopentracing.FollowsFrom(previous.Context()),
+		goopentracing.Tag{Key: "attempt", Value: 2},
+	)
+	span.Finish()
+
+	recorded := findSpan(t, rec.Flush(), "Child")
+	parentCtx := parent.Context().(SpanContext)
+
+	if recorded.TraceID.String() != parentCtx.TraceID() || recorded.ParentID == nil || recorded.ParentID.String() != parentCtx.SpanID() {
+		t.Errorf("expected child of %s, got parent %v", parentCtx.SpanID(), recorded.ParentID)
+	}
+
+	expected := map[string]string{
+		"attempt":        "2",
+		"link.0.type":    "link",
+		"link.0.span_id": other.Context().(SpanContext).SpanID(),
+		"link.1.type":    "follows_from",
+		"link.1.span_id": previous.Context().(SpanContext).SpanID(),
+	}
+	for key, value := range expected {
+		if recorded.Tags[key] != value {
+			t.Errorf("%s: expected %q, got %q", key, value, recorded.Tags[key])
+		}
+	}
+}
+
+func TestStartSpanFollowsFromOnly(t *testing.T) {
+	bridge, _, rec := newTestTracer(t)
+
+	previous := bridge.StartSpan("Previous")
+	span := bridge.StartSpan("Next", goopentracing.FollowsFrom(previous.Context()))
+	span.Finish()
+
+	recorded := findSpan(t, rec.Flush(), "Next")
+	previousCtx := previous.Context().(SpanContext)
+
+	if recorded.ParentID == nil || recorded.ParentID.String() != previousCtx.SpanID() {
+		t.Errorf("expected follows-from reference to become the parent, got %v", recorded.ParentID)
+	}
+	if recorded.Tags["reference_type"] != "follows_from" {
+		t.Errorf("expected reference_type tag, got %v", recorded.Tags)
+	}
+}
+
+func TestInjectExtract(t *testing.T) {
+	bridge, _, _ := newTestTracer(t)
+
+	span := bridge.StartSpan("Request")
+	spanCtx := span.Context().(SpanContext)
+
+	cases := map[interface{}]func() (interface{}, interface{}){
+		goopentracing.TextMap: func() (interface{}, interface{}) {
+			carrier := goopentracing.TextMapCarrier{}
+			return carrier, carrier
+		},
+		goopentracing.HTTPHeaders: func() (interface{}, interface{}) {
+			carrier := goopentracing.HTTPHeadersCarrier(http.Header{})
+			return carrier, carrier
+		},
+		goopentracing.Binary: func() (interface{}, interface{}) {
+			buffer := &bytes.Buffer{}
+			return buffer, buffer
+		},
+	}
+
+	for format, carrier := range cases {
+		writer, reader := carrier()
+
+		if err := bridge.Inject(span.Context(), format, writer); err != nil {
+			t.Fatalf("%v: %v", format, err)
+		}
+
+		extracted, err := bridge.Extract(format, reader)
+		if err != nil {
+			t.Fatalf("%v: %v", format, err)
+		}
+
+		extractedCtx := extracted.(SpanContext)
+		if extractedCtx.TraceID() != spanCtx.TraceID() || extractedCtx.SpanID() != spanCtx.SpanID() {
+			t.Errorf("%v: expected %s/%s, got %s/%s", format, spanCtx.TraceID(), spanCtx.SpanID(), extractedCtx.TraceID(), extractedCtx.SpanID())
+		}
+	}
+}
+
+func TestInjectExtractErrors(t *testing.T) {
+	bridge, _, _ := newTestTracer(t)
+	spanCtx := bridge.StartSpan("Request").Context()
+
+	if err := bridge.Inject(spanCtx, goopentracing.HTTPHeaders, goopentracing.TextMapCarrier{}); err != goopentracing.ErrInvalidCarrier {
+		t.Errorf("expected %v, got %v", goopentracing.ErrInvalidCarrier, err)
+	}
+	if err := bridge.Inject(spanCtx, "custom", goopentracing.TextMapCarrier{}); err != goopentracing.ErrUnsupportedFormat {
+		t.Errorf("expected %v, got %v", goopentracing.ErrUnsupportedFormat, err)
+	}
+	if _, err := bridge.Extract(goopentracing.TextMap, goopentracing.TextMapCarrier{}); err != goopentracing.ErrSpanContextNotFound {
+		t.Errorf("expected %v, got %v", goopentracing.ErrSpanContextNotFound, err)
+	}
+	if _, err := bridge.Extract(goopentracing.Binary, bytes.NewBufferString("garbage")); err != goopentracing.ErrSpanContextCorrupted {
+		t.Errorf("expected %v, got %v", goopentracing.ErrSpanContextCorrupted, err)
+	}
+}
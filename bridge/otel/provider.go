@@ -0,0 +1,28 @@
+package otel
+
+import (
+	"github.com/Vinelab/tracing-go"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/embedded"
+)
+
+// TracerProvider exposes tracing.Tracer as OpenTelemetry TracerProvider, so that spans created by
+// OpenTelemetry instrumentation libraries land in the same traces. It should be initialized using
+// NewTracerProvider method.
+type TracerProvider struct {
+	embedded.TracerProvider
+
+	tracer tracing.Tracer
+}
+
+// NewTracerProvider returns a new TracerProvider
+func NewTracerProvider(tracer tracing.Tracer) *TracerProvider {
+	return &TracerProvider{tracer: tracer}
+}
+
+// Tracer returns a Tracer recording the name of instrumentation scope as otel.scope.name tag
+func (provider *TracerProvider) Tracer(name string, options ...trace.TracerOption) trace.Tracer {
+	cfg := trace.NewTracerConfig(options...)
+
+	return &Tracer{provider: provider, scopeName: name, scopeVersion: cfg.InstrumentationVersion()}
+}
@@ -0,0 +1,129 @@
+package otel
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/Vinelab/tracing-go"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/embedded"
+)
+
+// Span exposes tracing.Span as OpenTelemetry span. Attributes are recorded as tags,
+// events (including recorded errors) as logs and error status as error tag.
+type Span struct {
+	embedded.Span
+
+	span     tracing.Span
+	provider *TracerProvider
+
+	mu    sync.Mutex
+	ended bool
+}
+
+// Unwrap returns the underlying span
+func (span *Span) Unwrap() tracing.Span {
+	return span.span
+}
+
+// End completes the span, only once
+func (span *Span) End(options ...trace.SpanEndOption) {
+	span.mu.Lock()
+	ended := span.ended
+	span.ended = true
+	span.mu.Unlock()
+
+	if ended {
+		return
+	}
+
+	cfg := trace.NewSpanEndConfig(options...)
+	if cfg.Timestamp().IsZero() {
+		span.span.Finish()
+	} else {
+		span.span.FinishAt(cfg.Timestamp())
+	}
+}
+
+// AddEvent records the event as a log with the event field set to its name
+func (span *Span) AddEvent(name string, options ...trace.EventOption) {
+	cfg := trace.NewEventConfig(options...)
+
+	fields := attributesToFields(cfg.Attributes())
+	fields["event"] = name
+
+	span.span.LogAt(cfg.Timestamp(), fields)
+}
+
+// IsRecording tells whether the span has not ended yet
+func (span *Span) IsRecording() bool {
+	span.mu.Lock()
+	defer span.mu.Unlock()
+
+	return !span.ended
+}
+
+// RecordError records the error as a log of exception event following OpenTelemetry semantic conventions
+func (span *Span) RecordError(err error, options ...trace.EventOption) {
+	if err == nil {
+		return
+	}
+
+	options = append(options, trace.WithAttributes(
+		attribute.String("exception.type", fmt.Sprintf("%T", err)),
+		attribute.String("exception.message", err.Error()),
+	))
+
+	span.AddEvent("exception", options...)
+}
+
+// SpanContext returns OpenTelemetry representation of the span context
+func (span *Span) SpanContext() trace.SpanContext {
+	return toOtelContext(span.span.Context())
+}
+
+// SetStatus records the status as otel.status_code tag, error status is also recorded as error tag
+func (span *Span) SetStatus(code codes.Code, description string) {
+	if code == codes.Unset {
+		return
+	}
+
+	span.span.Tag("otel.status_code", strings.ToUpper(code.String()))
+
+	if code == codes.Error {
+		if description == "" {
+			description = "true"
+		}
+		span.span.Tag("error", description)
+	}
+}
+
+// SetName sets (overrides) the name of the span
+func (span *Span) SetName(name string) {
+	span.span.SetName(name)
+}
+
+// SetAttributes records attributes as tags
+func (span *Span) SetAttributes(kv ...attribute.KeyValue) {
+	for key, value := range attributesToFields(kv) {
+		span.span.Tag(key, value)
+	}
+}
+
+// TracerProvider returns the provider that created this span
+func (span *Span) TracerProvider() trace.TracerProvider {
+	return span.provider
+}
+
+// attributesToFields converts attributes into strings, slices are encoded as JSON arrays
+func attributesToFields(attributes []attribute.KeyValue) map[string]string {
+	fields := make(map[string]string, len(attributes))
+	for _, kv := range attributes {
+		fields[string(kv.Key)] = kv.Value.Emit()
+	}
+
+	return fields
+}
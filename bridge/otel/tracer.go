@@ -0,0 +1,135 @@
+package otel
+
+import (
+	"context"
+	"strings"
+
+	"github.com/Vinelab/tracing-go"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/embedded"
+)
+
+// Tracer starts spans using the underlying tracing.Tracer
+type Tracer struct {
+	embedded.Tracer
+
+	provider     *TracerProvider
+	scopeName    string
+	scopeVersion string
+}
+
+// Start starts a new span. The parent is taken from the OpenTelemetry span (or span context) in ctx,
+// falling back to the span stored in ctx by tracing.ContextWithSpan and the current span of the tracer.
+//
+// The returned context holds the span for both OpenTelemetry and tracing.SpanFromContext.
+func (tracer *Tracer) Start(ctx context.Context, spanName string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	cfg := trace.NewSpanStartConfig(opts...)
+	underlying := tracer.provider.tracer
+
+	parent := underlying.EmptySpanContext()
+	if !cfg.NewRoot() {
+		if spanCtx := tracer.parent(ctx); spanCtx != nil {
+			parent = spanCtx
+		}
+	}
+
+	startOpts := make([]tracing.StartSpanOption, 0, len(cfg.Links())+2)
+	if !cfg.Timestamp().IsZero() {
+		startOpts = append(startOpts, tracing.StartTime(cfg.Timestamp()))
+	}
+
+	for _, link := range cfg.Links() {
+		linked := fromOtelContext(underlying, link.SpanContext)
+		if linked == nil {
+			continue
+		}
+
+		startOpts = append(startOpts, tracing.Link(linked, attributesToFields(link.Attributes)))
+	}
+
+	if kind := cfg.SpanKind(); kind != trace.SpanKindUnspecified && kind != trace.SpanKindInternal {
+		startOpts = append(startOpts, tracing.Kind(tracing.SpanKind(kind.String())))
+	}
+
+	span := underlying.StartSpan(spanName, parent, startOpts...)
+
+	if tracer.scopeName != "" {
+		span.Tag("otel.scope.name", tracer.scopeName)
+	}
+	if tracer.scopeVersion != "" {
+		span.Tag("otel.scope.version", tracer.scopeVersion)
+	}
+	for key, value := range attributesToFields(cfg.Attributes()) {
+		span.Tag(key, value)
+	}
+
+	otelSpan := &Span{span: span, provider: tracer.provider}
+
+	ctx = tracing.ContextWithSpan(ctx, span)
+	return trace.ContextWithSpan(ctx, otelSpan), otelSpan
+}
+
+func (tracer *Tracer) parent(ctx context.Context) tracing.SpanContext {
+	if span, ok := trace.SpanFromContext(ctx).(*Span); ok {
+		return span.span.Context()
+	}
+
+	if otelCtx := trace.SpanContextFromContext(ctx); otelCtx.IsValid() {
+		return fromOtelContext(tracer.provider.tracer, otelCtx)
+	}
+
+	if span := tracing.ActiveSpan(ctx, tracer.provider.tracer); span != nil {
+		return span.Context()
+	}
+
+	return nil
+}
+
+// fromOtelContext converts OpenTelemetry span context (i.e. extracted by OpenTelemetry propagator)
+// into the span context of the tracer, provided the tracer implements tracing.SpanContextBuilder
+func fromOtelContext(tracer tracing.Tracer, otelCtx trace.SpanContext) tracing.SpanContext {
+	builder, ok := tracer.(tracing.SpanContextBuilder)
+	if !ok || !otelCtx.IsValid() {
+		return nil
+	}
+
+	spanCtx, err := builder.BuildSpanContext(otelCtx.TraceID().String(), otelCtx.SpanID().String(), otelCtx.IsSampled())
+	if err != nil || !spanCtx.IsValid() {
+		return nil
+	}
+
+	return spanCtx
+}
+
+// toOtelContext converts the span context of the tracer into OpenTelemetry span context
+func toOtelContext(spanCtx tracing.SpanContext) trace.SpanContext {
+	traceID, err := trace.TraceIDFromHex(leftPad(spanCtx.TraceID(), 32))
+	if err != nil {
+		return trace.SpanContext{}
+	}
+
+	spanID, err := trace.SpanIDFromHex(leftPad(spanCtx.SpanID(), 16))
+	if err != nil {
+		return trace.SpanContext{}
+	}
+
+	var flags trace.TraceFlags
+	if spanCtx.IsSampled() {
+		flags = trace.FlagsSampled
+	}
+
+	return trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: flags,
+	})
+}
+
+// leftPad pads 64 bit trace IDs with zeros
+func leftPad(id string, length int) string {
+	if id == "" || len(id) >= length {
+		return id
+	}
+
+	return strings.Repeat("0", length-len(id)) + id
+}
@@ -0,0 +1,48 @@
+package otel
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Vinelab/tracing-go/drivers/zipkin"
+	"github.com/Vinelab/tracing-go/formats"
+	"github.com/openzipkin/zipkin-go/reporter/recorder"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestRemoteParentContinuedRegardlessOfTextMapFormat(t *testing.T) {
+	rec := recorder.NewReporter()
+	tracer, err := zipkin.NewTracer(zipkin.TracerOptions{ServiceName: "bridge-test", Reporter: rec})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Conversion must not depend on the header style TextMap format happens to use
+	tracer.RegisterExtractionFormat(formats.TextMap, zipkin.NewW3CExtractor())
+
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	parent := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: trace.FlagsSampled,
+		Remote:     true,
+	})
+
+	ctx := trace.ContextWithRemoteSpanContext(context.Background(), parent)
+	_, span := NewTracerProvider(tracer).Tracer("test").Start(ctx, "Instrumented")
+	span.End()
+
+	spans := rec.Flush()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+
+	if spans[0].TraceID.String() != traceID.String() {
+		t.Errorf("expected trace %s, got %s", traceID, spans[0].TraceID)
+	}
+
+	if spans[0].ParentID == nil || spans[0].ParentID.String() != spanID.String() {
+		t.Errorf("expected parent %s, got %v", spanID, spans[0].ParentID)
+	}
+}
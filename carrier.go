@@ -0,0 +1,24 @@
+package tracing
+
+// TextMapReader is a carrier trace context can be extracted from. Implement it
+// to propagate trace context over transports that are not supported out of the box.
+type TextMapReader interface {
+	// Get returns the value associated with the key or empty string if there is none
+	Get(key string) string
+
+	// Keys lists the keys held by the carrier
+	Keys() []string
+}
+
+// TextMapWriter is a carrier trace context can be injected into. Implement it
+// to propagate trace context over transports that are not supported out of the box.
+type TextMapWriter interface {
+	// Set associates the value with the key, overriding the existing one
+	Set(key string, value string)
+}
+
+// TextMapCarrier is a carrier trace context can be both extracted from and injected into
+type TextMapCarrier interface {
+	TextMapReader
+	TextMapWriter
+}
@@ -0,0 +1,128 @@
+// Package amqp adapts streadway/amqp messages to tracing carriers. Once imported, carriers.NewReader
+// and carriers.NewWriter accept amqp.Table, *amqp.Delivery and *amqp.Publishing.
+package amqp
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Vinelab/tracing-go"
+	"github.com/Vinelab/tracing-go/carriers"
+	goamqp "github.com/streadway/amqp"
+)
+
+func init() {
+	carriers.RegisterReader(NewReader)
+	carriers.RegisterWriter(NewWriter)
+}
+
+// NewReader adapts amqp.Table, *amqp.Delivery and *amqp.Publishing to tracing.TextMapReader
+func NewReader(carrier interface{}) (tracing.TextMapReader, bool) {
+	switch c := carrier.(type) {
+	case goamqp.Table:
+		return Table(c), true
+	case *goamqp.Delivery:
+		return Table(c.Headers), true
+	case *goamqp.Publishing:
+		return Table(c.Headers), true
+	}
+
+	return nil, false
+}
+
+// NewWriter adapts amqp.Table and *amqp.Publishing to tracing.TextMapWriter
+func NewWriter(carrier interface{}) (tracing.TextMapWriter, bool) {
+	switch c := carrier.(type) {
+	case goamqp.Table:
+		if c != nil {
+			return Table(c), true
+		}
+	case *goamqp.Publishing:
+		if c.Headers == nil {
+			c.Headers = goamqp.Table{}
+		}
+		return Table(c.Headers), true
+	}
+
+	return nil, false
+}
+
+// Table adapts AMQP headers to tracing.TextMapCarrier. Keys are case-insensitive, since publishers
+// in other languages may send i.e. x-b3-traceid in place of X-B3-TraceId
+type Table goamqp.Table
+
+// Get returns the value associated with the key. Besides strings, byte slices (AMQP long strings
+// sent by some clients) and integers are converted, other values are ignored. The exact key is
+// preferred, falling back to the case-insensitive matches in lexical order.
+func (carrier Table) Get(key string) string {
+	if value, ok := amqpString(carrier[key]); ok {
+		return value
+	}
+
+	matches := make([]string, 0, 1)
+	for k := range carrier {
+		if k != key && strings.EqualFold(k, key) {
+			matches = append(matches, k)
+		}
+	}
+	sort.Strings(matches)
+
+	for _, k := range matches {
+		if value, ok := amqpString(carrier[k]); ok {
+			return value
+		}
+	}
+
+	return ""
+}
+
+// Set associates the value with the key, replacing keys that only differ in case
+func (carrier Table) Set(key string, value string) {
+	for k := range carrier {
+		if k != key && strings.EqualFold(k, key) {
+			delete(carrier, k)
+		}
+	}
+
+	carrier[key] = value
+}
+
+// Keys lists the keys of the headers
+func (carrier Table) Keys() []string {
+	keys := make([]string, 0, len(carrier))
+	for key := range carrier {
+		keys = append(keys, key)
+	}
+
+	return keys
+}
+
+func amqpString(value interface{}) (string, bool) {
+	switch v := value.(type) {
+	case string:
+		return v, true
+	case []byte:
+		return string(v), true
+	case int:
+		return strconv.FormatInt(int64(v), 10), true
+	case int8:
+		return strconv.FormatInt(int64(v), 10), true
+	case int16:
+		return strconv.FormatInt(int64(v), 10), true
+	case int32:
+		return strconv.FormatInt(int64(v), 10), true
+	case int64:
+		return strconv.FormatInt(v, 10), true
+	case uint8:
+		return strconv.FormatUint(uint64(v), 10), true
+	case uint16:
+		return strconv.FormatUint(uint64(v), 10), true
+	case uint32:
+		return strconv.FormatUint(uint64(v), 10), true
+	case uint64:
+		return strconv.FormatUint(v, 10), true
+	}
+
+	return "", false
+}
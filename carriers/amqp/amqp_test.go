@@ -0,0 +1,87 @@
+package amqp
+
+import (
+	"testing"
+
+	"github.com/Vinelab/tracing-go/carriers"
+	goamqp "github.com/streadway/amqp"
+)
+
+// Headers of messages published by RabbitMQ clients in other languages as streadway/amqp decodes them
+var amqpClientMessages = map[string]goamqp.Table{
+	// Java (Brave spring-rabbit) sends canonical keys as long strings
+	"java": {
+		"X-B3-TraceId": "463ac35c9f6413ad",
+		"X-B3-SpanId":  "72485a3953bb6124",
+		"X-B3-Sampled": "1",
+	},
+	// Python (pika) libraries tend to lowercase the keys
+	"python": {
+		"x-b3-traceid": "463ac35c9f6413ad",
+		"x-b3-spanid":  "72485a3953bb6124",
+		"x-b3-sampled": "1",
+	},
+	// Node.js (amqplib) encodes numbers as integers and booleans as AMQP booleans
+	"nodejs": {
+		"x-b3-traceid": "463ac35c9f6413ad",
+		"x-b3-spanid":  "72485a3953bb6124",
+		"x-b3-sampled": int32(1),
+	},
+	// .NET (RabbitMQ.Client) sends byte arrays unless strings are explicitly encoded
+	"dotnet": {
+		"X-B3-TraceId": []byte("463ac35c9f6413ad"),
+		"X-B3-SpanId":  []byte("72485a3953bb6124"),
+		"X-B3-Sampled": []byte("1"),
+	},
+	// PHP (php-amqplib) sends integers as signed 64 bit values
+	"php": {
+		"X-B3-TraceId": "463ac35c9f6413ad",
+		"X-B3-SpanId":  "72485a3953bb6124",
+		"X-B3-Sampled": int64(1),
+	},
+	// Ruby (bunny) middleware sets boolean flag under the canonical key and string under the lowercase one
+	"ruby": {
+		"X-B3-TraceId": "463ac35c9f6413ad",
+		"X-B3-SpanId":  "72485a3953bb6124",
+		"X-B3-Sampled": true,
+		"x-b3-sampled": "1",
+	},
+}
+
+func TestTableClientMatrix(t *testing.T) {
+	expected := map[string]string{
+		"X-B3-TraceId": "463ac35c9f6413ad",
+		"X-B3-SpanId":  "72485a3953bb6124",
+		"X-B3-Sampled": "1",
+	}
+
+	for client, headers := range amqpClientMessages {
+		reader, err := carriers.NewReader(&goamqp.Delivery{Headers: headers})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for key, value := range expected {
+			if actual := reader.Get(key); actual != value {
+				t.Errorf("%s: expected %s=%q, got %q", client, key, value, actual)
+			}
+		}
+	}
+}
+
+func TestTableIgnoresUnconvertibleValues(t *testing.T) {
+	table := Table{"X-B3-Flags": true, "x-b3-flags": false}
+
+	if value := table.Get("X-B3-Flags"); value != "" {
+		t.Errorf("expected empty value, got %q", value)
+	}
+}
+
+func TestTableSetReplacesCaseVariants(t *testing.T) {
+	table := Table{"x-b3-traceid": "old"}
+	table.Set("X-B3-TraceId", "new")
+
+	if len(table) != 1 || table["X-B3-TraceId"] != "new" {
+		t.Errorf("expected the lowercase key to be replaced, got %v", table)
+	}
+}
@@ -0,0 +1,156 @@
+// Package aws adapts SQS and SNS messages of aws-sdk-go-v2 to tracing carriers. Once imported,
+// carriers.NewReader and carriers.NewWriter accept *sqs.SendMessageInput, *types.SendMessageBatchRequestEntry,
+// *sns.PublishInput and *types.PublishBatchRequestEntry, as well as received SQS *types.Message.
+package aws
+
+import (
+	"strings"
+
+	"github.com/Vinelab/tracing-go"
+	"github.com/Vinelab/tracing-go/carriers"
+	goaws "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sns"
+	snstypes "github.com/aws/aws-sdk-go-v2/service/sns/types"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	sqstypes "github.com/aws/aws-sdk-go-v2/service/sqs/types"
+)
+
+func init() {
+	carriers.RegisterReader(NewReader)
+	carriers.RegisterWriter(NewWriter)
+}
+
+// NewReader adapts message attributes of SQS and SNS messages to tracing.TextMapReader
+func NewReader(carrier interface{}) (tracing.TextMapReader, bool) {
+	switch c := carrier.(type) {
+	case *sqs.SendMessageInput:
+		return SQSAttributes(c.MessageAttributes), true
+	case *sqstypes.SendMessageBatchRequestEntry:
+		return SQSAttributes(c.MessageAttributes), true
+	case *sqstypes.Message:
+		return SQSAttributes(c.MessageAttributes), true
+	case *sns.PublishInput:
+		return SNSAttributes(c.MessageAttributes), true
+	case *snstypes.PublishBatchRequestEntry:
+		return SNSAttributes(c.MessageAttributes), true
+	}
+
+	return nil, false
+}
+
+// NewWriter adapts message attributes of outgoing SQS and SNS messages to tracing.TextMapWriter
+func NewWriter(carrier interface{}) (tracing.TextMapWriter, bool) {
+	switch c := carrier.(type) {
+	case *sqs.SendMessageInput:
+		if c.MessageAttributes == nil {
+			c.MessageAttributes = make(map[string]sqstypes.MessageAttributeValue)
+		}
+		return SQSAttributes(c.MessageAttributes), true
+	case *sqstypes.SendMessageBatchRequestEntry:
+		if c.MessageAttributes == nil {
+			c.MessageAttributes = make(map[string]sqstypes.MessageAttributeValue)
+		}
+		return SQSAttributes(c.MessageAttributes), true
+	case *sns.PublishInput:
+		if c.MessageAttributes == nil {
+			c.MessageAttributes = make(map[string]snstypes.MessageAttributeValue)
+		}
+		return SNSAttributes(c.MessageAttributes), true
+	case *snstypes.PublishBatchRequestEntry:
+		if c.MessageAttributes == nil {
+			c.MessageAttributes = make(map[string]snstypes.MessageAttributeValue)
+		}
+		return SNSAttributes(c.MessageAttributes), true
+	}
+
+	return nil, false
+}
+
+// attributeDataType is the data type of message attributes written by the carriers
+const attributeDataType = "String"
+
+// SQSAttributes adapts SQS message attributes to tracing.TextMapCarrier. Values are written
+// as String attributes, String, Number and Binary attributes are read.
+type SQSAttributes map[string]sqstypes.MessageAttributeValue
+
+// Get returns the value associated with the key
+func (carrier SQSAttributes) Get(key string) string {
+	value, ok := carrier[key]
+	if !ok {
+		return ""
+	}
+
+	return attributeString(value.DataType, value.StringValue, value.BinaryValue)
+}
+
+// Set associates the value with the key
+func (carrier SQSAttributes) Set(key string, value string) {
+	carrier[key] = sqstypes.MessageAttributeValue{
+		DataType:    goaws.String(attributeDataType),
+		StringValue: goaws.String(value),
+	}
+}
+
+// Del removes the attribute associated with the key
+func (carrier SQSAttributes) Del(key string) {
+	delete(carrier, key)
+}
+
+// Keys lists the names of the attributes
+func (carrier SQSAttributes) Keys() []string {
+	keys := make([]string, 0, len(carrier))
+	for key := range carrier {
+		keys = append(keys, key)
+	}
+
+	return keys
+}
+
+// SNSAttributes adapts SNS message attributes to tracing.TextMapCarrier. Values are written
+// as String attributes, String, Number and Binary attributes are read.
+type SNSAttributes map[string]snstypes.MessageAttributeValue
+
+// Get returns the value associated with the key
+func (carrier SNSAttributes) Get(key string) string {
+	value, ok := carrier[key]
+	if !ok {
+		return ""
+	}
+
+	return attributeString(value.DataType, value.StringValue, value.BinaryValue)
+}
+
+// Set associates the value with the key
+func (carrier SNSAttributes) Set(key string, value string) {
+	carrier[key] = snstypes.MessageAttributeValue{
+		DataType:    goaws.String(attributeDataType),
+		StringValue: goaws.String(value),
+	}
+}
+
+// Del removes the attribute associated with the key
+func (carrier SNSAttributes) Del(key string) {
+	delete(carrier, key)
+}
+
+// Keys lists the names of the attributes
+func (carrier SNSAttributes) Keys() []string {
+	keys := make([]string, 0, len(carrier))
+	for key := range carrier {
+		keys = append(keys, key)
+	}
+
+	return keys
+}
+
+// attributeString converts attribute value of given data type (including custom types, i.e. String.TraceID)
+func attributeString(dataType *string, stringValue *string, binaryValue []byte) string {
+	switch {
+	case strings.HasPrefix(goaws.ToString(dataType), "Binary"):
+		return string(binaryValue)
+	case stringValue != nil:
+		return *stringValue
+	}
+
+	return ""
+}
@@ -0,0 +1,165 @@
+package carriers
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/Vinelab/tracing-go"
+)
+
+// ReaderAdapter adapts carriers of a transport to tracing.TextMapReader, reporting false for unknown carriers
+type ReaderAdapter func(carrier interface{}) (tracing.TextMapReader, bool)
+
+// WriterAdapter adapts carriers of a transport to tracing.TextMapWriter, reporting false for unknown carriers
+type WriterAdapter func(carrier interface{}) (tracing.TextMapWriter, bool)
+
+var (
+	adaptersMu     sync.RWMutex
+	readerAdapters []ReaderAdapter
+	writerAdapters []WriterAdapter
+)
+
+// RegisterReader makes carriers of a transport readable by NewReader, so that all header styles work with them.
+// Adapters of the carriers/* packages (i.e. carriers/amqp) register themselves once the package is imported.
+func RegisterReader(adapter ReaderAdapter) {
+	adaptersMu.Lock()
+	defer adaptersMu.Unlock()
+
+	readerAdapters = append(readerAdapters, adapter)
+}
+
+// RegisterWriter makes carriers of a transport writable by NewWriter, so that all header styles work with them.
+// Adapters of the carriers/* packages (i.e. carriers/amqp) register themselves once the package is imported.
+func RegisterWriter(adapter WriterAdapter) {
+	adaptersMu.Lock()
+	defer adaptersMu.Unlock()
+
+	writerAdapters = append(writerAdapters, adapter)
+}
+
+// UnsupportedCarrierError is returned when the carrier can't be adapted to tracing.TextMapReader or tracing.TextMapWriter
+type UnsupportedCarrierError struct {
+	carrier interface{}
+}
+
+// Error returns the string representation of the error
+func (e *UnsupportedCarrierError) Error() string {
+	return fmt.Sprintf("Unsupported carrier %T", e.carrier)
+}
+
+// NewReader adapts the carrier to tracing.TextMapReader. Supported carriers are the ones
+// implementing tracing.TextMapReader, map[string]string, *map[string]string, http.Header,
+// *http.Request and carriers of registered adapters (see RegisterReader)
+func NewReader(carrier interface{}) (tracing.TextMapReader, error) {
+	switch c := carrier.(type) {
+	case tracing.TextMapReader:
+		return c, nil
+	case map[string]string:
+		return TextMap(c), nil
+	case *map[string]string:
+		return TextMap(*c), nil
+	case http.Header:
+		return HTTPHeader(c), nil
+	case *http.Request:
+		return HTTPHeader(c.Header), nil
+	}
+
+	adaptersMu.RLock()
+	defer adaptersMu.RUnlock()
+
+	for _, adapter := range readerAdapters {
+		if reader, ok := adapter(carrier); ok {
+			return reader, nil
+		}
+	}
+
+	return nil, &UnsupportedCarrierError{carrier: carrier}
+}
+
+// NewWriter adapts the carrier to tracing.TextMapWriter. Supported carriers are the ones
+// implementing tracing.TextMapWriter, map[string]string, *map[string]string, http.Header,
+// *http.Request and carriers of registered adapters (see RegisterWriter)
+func NewWriter(carrier interface{}) (tracing.TextMapWriter, error) {
+	switch c := carrier.(type) {
+	case tracing.TextMapWriter:
+		return c, nil
+	case map[string]string:
+		if c != nil {
+			return TextMap(c), nil
+		}
+		return nil, &UnsupportedCarrierError{carrier: carrier}
+	case *map[string]string:
+		if *c == nil {
+			*c = make(map[string]string)
+		}
+		return TextMap(*c), nil
+	case http.Header:
+		if c != nil {
+			return HTTPHeader(c), nil
+		}
+		return nil, &UnsupportedCarrierError{carrier: carrier}
+	case *http.Request:
+		if c.Header == nil {
+			c.Header = make(http.Header)
+		}
+		return HTTPHeader(c.Header), nil
+	}
+
+	adaptersMu.RLock()
+	defer adaptersMu.RUnlock()
+
+	for _, adapter := range writerAdapters {
+		if writer, ok := adapter(carrier); ok {
+			return writer, nil
+		}
+	}
+
+	return nil, &UnsupportedCarrierError{carrier: carrier}
+}
+
+// TextMap adapts a map to tracing.TextMapCarrier
+type TextMap map[string]string
+
+// Get returns the value associated with the key
+func (carrier TextMap) Get(key string) string {
+	return carrier[key]
+}
+
+// Set associates the value with the key
+func (carrier TextMap) Set(key string, value string) {
+	carrier[key] = value
+}
+
+// Keys lists the keys held by the map
+func (carrier TextMap) Keys() []string {
+	keys := make([]string, 0, len(carrier))
+	for key := range carrier {
+		keys = append(keys, key)
+	}
+
+	return keys
+}
+
+// HTTPHeader adapts HTTP headers to tracing.TextMapCarrier, keys are case-insensitive
+type HTTPHeader http.Header
+
+// Get returns the first value associated with the key
+func (carrier HTTPHeader) Get(key string) string {
+	return http.Header(carrier).Get(key)
+}
+
+// Set replaces values associated with the key
+func (carrier HTTPHeader) Set(key string, value string) {
+	http.Header(carrier).Set(key, value)
+}
+
+// Keys lists canonical keys of the headers
+func (carrier HTTPHeader) Keys() []string {
+	keys := make([]string, 0, len(carrier))
+	for key := range carrier {
+		keys = append(keys, key)
+	}
+
+	return keys
+}
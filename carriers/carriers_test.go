@@ -0,0 +1,72 @@
+package carriers
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/Vinelab/tracing-go"
+)
+
+// message is a carrier of a transport unknown to the package
+type message struct {
+	headers map[string]string
+}
+
+func TestRegisteredAdapters(t *testing.T) {
+	msg := &message{}
+
+	RegisterReader(func(carrier interface{}) (tracing.TextMapReader, bool) {
+		if m, ok := carrier.(*message); ok {
+			return TextMap(m.headers), true
+		}
+		return nil, false
+	})
+	RegisterWriter(func(carrier interface{}) (tracing.TextMapWriter, bool) {
+		if m, ok := carrier.(*message); ok {
+			if m.headers == nil {
+				m.headers = make(map[string]string)
+			}
+			return TextMap(m.headers), true
+		}
+		return nil, false
+	})
+
+	writer, err := NewWriter(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writer.Set("traceparent", "value")
+
+	reader, err := NewReader(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reader.Get("traceparent") != "value" {
+		t.Errorf("expected value written through the adapter, got %v", msg.headers)
+	}
+
+	if _, err := NewReader(struct{}{}); err == nil {
+		t.Error("expected carriers unknown to adapters to be unsupported")
+	}
+}
+
+func TestNewWriterStandardCarriers(t *testing.T) {
+	var textMap map[string]string
+	req := &http.Request{}
+
+	for _, carrier := range []interface{}{&textMap, req, http.Header{}, map[string]string{}} {
+		writer, err := NewWriter(carrier)
+		if err != nil {
+			t.Fatalf("%T: %v", carrier, err)
+		}
+		writer.Set("X-B3-TraceId", "463ac35c9f6413ad")
+	}
+
+	if textMap["X-B3-TraceId"] == "" || req.Header.Get("X-B3-TraceId") == "" {
+		t.Errorf("expected nil maps to be initialized, got %v and %v", textMap, req.Header)
+	}
+
+	if _, err := NewWriter(map[string]string(nil)); err == nil {
+		t.Error("expected nil map to be unsupported")
+	}
+}
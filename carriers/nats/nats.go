@@ -0,0 +1,85 @@
+// Package nats adapts nats.go messages to tracing carriers. Once imported, carriers.NewReader
+// and carriers.NewWriter accept nats.Header and *nats.Msg.
+package nats
+
+import (
+	"strings"
+
+	"github.com/Vinelab/tracing-go"
+	"github.com/Vinelab/tracing-go/carriers"
+	gonats "github.com/nats-io/nats.go"
+)
+
+func init() {
+	carriers.RegisterReader(NewReader)
+	carriers.RegisterWriter(NewWriter)
+}
+
+// NewReader adapts nats.Header and *nats.Msg to tracing.TextMapReader
+func NewReader(carrier interface{}) (tracing.TextMapReader, bool) {
+	switch c := carrier.(type) {
+	case gonats.Header:
+		return Header(c), true
+	case *gonats.Msg:
+		return Header(c.Header), true
+	}
+
+	return nil, false
+}
+
+// NewWriter adapts nats.Header and *nats.Msg to tracing.TextMapWriter
+func NewWriter(carrier interface{}) (tracing.TextMapWriter, bool) {
+	switch c := carrier.(type) {
+	case gonats.Header:
+		if c != nil {
+			return Header(c), true
+		}
+	case *gonats.Msg:
+		if c.Header == nil {
+			c.Header = gonats.Header{}
+		}
+		return Header(c.Header), true
+	}
+
+	return nil, false
+}
+
+// Header adapts NATS message headers to tracing.TextMapCarrier. NATS preserves the case of keys,
+// so the exact key is preferred, falling back to the case-insensitive match
+type Header gonats.Header
+
+// Get returns the first value associated with the key
+func (carrier Header) Get(key string) string {
+	if values, ok := carrier[key]; ok && len(values) > 0 {
+		return values[0]
+	}
+
+	for k, values := range carrier {
+		if strings.EqualFold(k, key) && len(values) > 0 {
+			return values[0]
+		}
+	}
+
+	return ""
+}
+
+// Set replaces values associated with the key, including keys that only differ in case
+func (carrier Header) Set(key string, value string) {
+	for k := range carrier {
+		if k != key && strings.EqualFold(k, key) {
+			delete(carrier, k)
+		}
+	}
+
+	carrier[key] = []string{value}
+}
+
+// Keys lists the keys of the headers
+func (carrier Header) Keys() []string {
+	keys := make([]string, 0, len(carrier))
+	for key := range carrier {
+		keys = append(keys, key)
+	}
+
+	return keys
+}
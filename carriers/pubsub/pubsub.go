@@ -0,0 +1,37 @@
+// Package pubsub adapts Google Cloud PubSub messages to tracing carriers. Once imported, carriers.NewReader
+// and carriers.NewWriter accept *pubsub.Message.
+package pubsub
+
+import (
+	gopubsub "cloud.google.com/go/pubsub"
+	"github.com/Vinelab/tracing-go"
+	"github.com/Vinelab/tracing-go/carriers"
+)
+
+func init() {
+	carriers.RegisterReader(NewReader)
+	carriers.RegisterWriter(NewWriter)
+}
+
+// NewReader adapts attributes of *pubsub.Message to tracing.TextMapReader
+func NewReader(carrier interface{}) (tracing.TextMapReader, bool) {
+	if msg, ok := carrier.(*gopubsub.Message); ok {
+		return carriers.TextMap(msg.Attributes), true
+	}
+
+	return nil, false
+}
+
+// NewWriter adapts attributes of *pubsub.Message to tracing.TextMapWriter
+func NewWriter(carrier interface{}) (tracing.TextMapWriter, bool) {
+	msg, ok := carrier.(*gopubsub.Message)
+	if !ok {
+		return nil, false
+	}
+
+	if msg.Attributes == nil {
+		msg.Attributes = make(map[string]string)
+	}
+
+	return carriers.TextMap(msg.Attributes), true
+}
@@ -0,0 +1,29 @@
+package tracing
+
+import (
+	"context"
+)
+
+type spanContextKey struct{}
+
+// ContextWithSpan returns a copy of parent context that carries the given span.
+// Use it to hand the active span over to code that accepts context.Context
+func ContextWithSpan(ctx context.Context, span Span) context.Context {
+	return context.WithValue(ctx, spanContextKey{}, span)
+}
+
+// SpanFromContext retrieves the span carried by the context or nil if there is none
+func SpanFromContext(ctx context.Context) Span {
+	span, _ := ctx.Value(spanContextKey{}).(Span)
+	return span
+}
+
+// ActiveSpan retrieves the span carried by the context and falls back
+// to the current span of the tracer when context does not carry one
+func ActiveSpan(ctx context.Context, tracer Tracer) Span {
+	if span := SpanFromContext(ctx); span != nil {
+		return span
+	}
+
+	return tracer.CurrentSpan()
+}
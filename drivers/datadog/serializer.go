@@ -0,0 +1,143 @@
+package datadog
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Vinelab/tracing-go/drivers/zipkin/propagation"
+	"github.com/openzipkin/zipkin-go/model"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+const (
+	// samplingPriorityKey is the metric Datadog agent reads sampling decision from
+	samplingPriorityKey = "_sampling_priority_v1"
+)
+
+// span is the representation of a span the agent accepts on /v0.4/traces endpoint
+type span struct {
+	Name     string             `msgpack:"name"`
+	Service  string             `msgpack:"service"`
+	Resource string             `msgpack:"resource"`
+	Type     string             `msgpack:"type"`
+	Start    int64              `msgpack:"start"`
+	Duration int64              `msgpack:"duration"`
+	Meta     map[string]string  `msgpack:"meta,omitempty"`
+	Metrics  map[string]float64 `msgpack:"metrics,omitempty"`
+	SpanID   uint64             `msgpack:"span_id"`
+	TraceID  uint64             `msgpack:"trace_id"`
+	ParentID uint64             `msgpack:"parent_id"`
+	Error    int32              `msgpack:"error"`
+}
+
+// Serializer encodes batches of spans into msgpack payload of Datadog agent, grouping them by trace.
+// It implements reporter.SpanSerializer, so it can be plugged into buffered.Reporter.
+type Serializer struct {
+	// Env is recorded as env tag of every span
+	Env string
+	// Version is recorded as version tag of every span
+	Version string
+}
+
+// Serialize encodes spans as a list of traces, each of them being a list of spans
+func (serializer Serializer) Serialize(spans []*model.SpanModel) ([]byte, error) {
+	traces := make([][]span, 0, 1)
+	indexes := make(map[model.TraceID]int)
+
+	for _, zipkinSpan := range spans {
+		index, ok := indexes[zipkinSpan.TraceID]
+		if !ok {
+			index = len(traces)
+			indexes[zipkinSpan.TraceID] = index
+			traces = append(traces, nil)
+		}
+
+		traces[index] = append(traces[index], serializer.convert(zipkinSpan))
+	}
+
+	buffer := bytes.Buffer{}
+	encoder := msgpack.NewEncoder(&buffer)
+	encoder.SetCustomStructTag("msgpack")
+
+	if err := encoder.Encode(traces); err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
+
+// ContentType returns the content type of the payload
+func (serializer Serializer) ContentType() string {
+	return "application/msgpack"
+}
+
+func (serializer Serializer) convert(zipkinSpan *model.SpanModel) span {
+	ddSpan := span{
+		Name:     zipkinSpan.Name,
+		Resource: zipkinSpan.Name,
+		Type:     spanType(zipkinSpan.Tags["type"]),
+		Start:    zipkinSpan.Timestamp.UnixNano(),
+		Duration: int64(zipkinSpan.Duration),
+		Meta:     make(map[string]string, len(zipkinSpan.Tags)+3),
+		Metrics:  map[string]float64{samplingPriorityKey: 1},
+		SpanID:   uint64(zipkinSpan.ID),
+		TraceID:  zipkinSpan.TraceID.Low,
+	}
+
+	if zipkinSpan.LocalEndpoint != nil {
+		ddSpan.Service = zipkinSpan.LocalEndpoint.ServiceName
+	}
+
+	if zipkinSpan.ParentID != nil {
+		ddSpan.ParentID = uint64(*zipkinSpan.ParentID)
+	}
+
+	// Unsampled spans are never reported, so the only decision left to tell is the one forced by debug flag
+	if zipkinSpan.Debug {
+		ddSpan.Metrics[samplingPriorityKey] = 2
+	}
+
+	for key, value := range zipkinSpan.Tags {
+		ddSpan.Meta[key] = value
+	}
+
+	if value, ok := zipkinSpan.Tags["error"]; ok {
+		ddSpan.Error = 1
+		if value != "true" {
+			ddSpan.Meta["error.message"] = value
+		}
+	}
+
+	if zipkinSpan.TraceID.High != 0 {
+		ddSpan.Meta[propagation.DatadogTraceIDHigh] = fmt.Sprintf("%016x", zipkinSpan.TraceID.High)
+	}
+
+	if serializer.Env != "" {
+		ddSpan.Meta["env"] = serializer.Env
+	}
+
+	if serializer.Version != "" {
+		ddSpan.Meta["version"] = serializer.Version
+	}
+
+	if len(zipkinSpan.Annotations) > 0 {
+		annotations := make([]string, 0, len(zipkinSpan.Annotations))
+		for _, annotation := range zipkinSpan.Annotations {
+			annotations = append(annotations, annotation.Timestamp.Format(time.RFC3339Nano)+" "+annotation.Value)
+		}
+		ddSpan.Meta["annotations"] = strings.Join(annotations, "\n")
+	}
+
+	return ddSpan
+}
+
+// spanType maps type tag recorded by middleware and hooks to Datadog span types
+func spanType(value string) string {
+	if value == "http" {
+		return "web"
+	}
+
+	return value
+}
@@ -0,0 +1,90 @@
+package datadog
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/Vinelab/tracing-go/drivers/zipkin"
+	"github.com/Vinelab/tracing-go/drivers/zipkin/buffered"
+)
+
+const (
+	// DefaultAgentHost is the host Datadog agent listens on by default
+	DefaultAgentHost = "localhost"
+	// DefaultAgentPort is the port of trace API of Datadog agent
+	DefaultAgentPort = "8126"
+)
+
+// TracerOptions is a configuration container to setup the Tracer.
+type TracerOptions struct {
+	// ServiceName is the name of application you're tracing
+	// Required
+	ServiceName string
+	// AgentURL is the base url of Datadog agent (i.e. http://localhost:8126)
+	// Defaults to DD_TRACE_AGENT_URL environment variable or the one made of DD_AGENT_HOST and DD_TRACE_AGENT_PORT
+	AgentURL string
+	// Env is recorded as env tag of every span
+	// Defaults to DD_ENV environment variable
+	Env string
+	// Version is recorded as version tag of every span
+	// Defaults to DD_VERSION environment variable
+	Version string
+	// UsesTraceID128Bit tells whether to use 128 bit trace IDs, upper 64 bits are propagated as _dd.p.tid tag
+	// Defaults to false
+	UsesTraceID128Bit bool
+	// Buffered configures queueing, batching and spooling of spans, see buffered.Options for details.
+	// Serializer is always set to the Datadog one
+	// Defaults to buffered reporter defaults
+	Buffered buffered.Options
+}
+
+// NewTracer returns a new tracer that sends spans to /v0.4/traces endpoint of Datadog agent. It is a Zipkin
+// tracer under the hood, so use formats.Datadog to propagate the trace context via x-datadog-* headers.
+func NewTracer(opt TracerOptions) (*zipkin.Tracer, error) {
+	env := opt.Env
+	if env == "" {
+		env = os.Getenv("DD_ENV")
+	}
+
+	version := opt.Version
+	if version == "" {
+		version = os.Getenv("DD_VERSION")
+	}
+
+	bufferedOpt := opt.Buffered
+	bufferedOpt.Serializer = Serializer{Env: env, Version: version}
+
+	rep, err := buffered.NewReporter(agentURL(opt.AgentURL)+"/v0.4/traces", bufferedOpt)
+	if err != nil {
+		return nil, err
+	}
+
+	return zipkin.NewTracer(zipkin.TracerOptions{
+		ServiceName:       opt.ServiceName,
+		UsesTraceID128Bit: opt.UsesTraceID128Bit,
+		Reporter:          rep,
+	})
+}
+
+func agentURL(url string) string {
+	if url == "" {
+		url = os.Getenv("DD_TRACE_AGENT_URL")
+	}
+
+	if url == "" {
+		host := os.Getenv("DD_AGENT_HOST")
+		if host == "" {
+			host = DefaultAgentHost
+		}
+
+		port := os.Getenv("DD_TRACE_AGENT_PORT")
+		if port == "" {
+			port = DefaultAgentPort
+		}
+
+		url = fmt.Sprintf("http://%s:%s", host, port)
+	}
+
+	return strings.TrimSuffix(url, "/")
+}
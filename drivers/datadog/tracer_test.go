@@ -0,0 +1,99 @@
+package datadog
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// agent records payloads sent to trace API
+type agent struct {
+	mu       sync.Mutex
+	paths    []string
+	types    []string
+	payloads [][]byte
+}
+
+func (a *agent) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+
+	a.mu.Lock()
+	a.paths = append(a.paths, r.URL.Path)
+	a.types = append(a.types, r.Header.Get("Content-Type"))
+	a.payloads = append(a.payloads, body)
+	a.mu.Unlock()
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func TestSpansSentToAgent(t *testing.T) {
+	a := &agent{}
+	srv := httptest.NewServer(a)
+	defer srv.Close()
+
+	tracer, err := NewTracer(TracerOptions{ServiceName: "datadog-test", AgentURL: srv.URL, Env: "test", Version: "1.0"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	root := tracer.StartSpan("HTTP Request", tracer.EmptySpanContext())
+	root.Tag("type", "http")
+	child := tracer.StartSpan("SQL SELECT", root.Context())
+	child.Tag("error", "connection reset")
+	child.Finish()
+	root.Finish()
+
+	if err := tracer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if len(a.payloads) != 1 {
+		t.Fatalf("expected 1 payload, got %d", len(a.payloads))
+	}
+
+	if a.paths[0] != "/v0.4/traces" || a.types[0] != "application/msgpack" {
+		t.Errorf("unexpected request to %s with %s", a.paths[0], a.types[0])
+	}
+
+	decoder := msgpack.NewDecoder(bytes.NewReader(a.payloads[0]))
+	decoder.SetCustomStructTag("msgpack")
+
+	var traces [][]span
+	if err := decoder.Decode(&traces); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(traces) != 1 || len(traces[0]) != 2 {
+		t.Fatalf("expected a single trace of 2 spans, got %+v", traces)
+	}
+
+	spans := map[string]span{}
+	for _, s := range traces[0] {
+		spans[s.Name] = s
+	}
+
+	request, query := spans["HTTP Request"], spans["SQL SELECT"]
+	if request.Type != "web" || request.Service != "datadog-test" || request.Meta["env"] != "test" || request.Meta["version"] != "1.0" {
+		t.Errorf("unexpected root span %+v", request)
+	}
+
+	if query.ParentID != request.SpanID || query.TraceID != request.TraceID {
+		t.Errorf("expected the query to be a child of the request, got %+v", query)
+	}
+
+	if query.Error != 1 || query.Meta["error.message"] != "connection reset" {
+		t.Errorf("expected the query to be erroneous, got %+v", query)
+	}
+
+	if request.Metrics[samplingPriorityKey] != 1 {
+		t.Errorf("expected sampling priority 1, got %v", request.Metrics)
+	}
+}
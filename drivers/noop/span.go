@@ -1,12 +1,17 @@
 package noop
 
 import (
+	"sync"
+	"time"
+
 	"github.com/Vinelab/tracing-go"
 )
 
 // Span encapsulates the state of logical operation it represents
 type Span struct {
-	isRoot bool
+	isRoot   bool
+	onFinish func(span *Span)
+	once     sync.Once
 }
 
 // NewSpan returns a new Span
@@ -28,7 +33,17 @@ func (span *Span) Tag(key string, value string) {
 // Finish notifies that operation has finished. Span duration is derived by subtracting the start
 // timestamp from this, and set when appropriate.
 func (span *Span) Finish() {
-	//
+	span.release()
+}
+
+// FinishAt notifies that operation has finished at the given timestamp.
+func (span *Span) FinishAt(timestamp time.Time) {
+	span.release()
+}
+
+// FinishWithDuration notifies that operation has finished after the given duration since the start.
+func (span *Span) FinishWithDuration(duration time.Duration) {
+	span.release()
 }
 
 // Annotate associates an event that explains latency with a timestamp.
@@ -36,12 +51,16 @@ func (span *Span) Annotate(message string) {
 	//
 }
 
-// Log stores structured data. Despite this functionality being outlined in
-// OpenTracing spec it's currently only supported in Jaeger
+// Log stores structured data.
 func (span *Span) Log(fields map[string]string) {
 	//
 }
 
+// LogAt stores structured data with the given timestamp. Use it for backfilled events
+func (span *Span) LogAt(timestamp time.Time, fields map[string]string) {
+	//
+}
+
 // IsRoot tells whether the span is a root span
 func (span *Span) IsRoot() bool {
 	return span.isRoot
@@ -51,3 +70,12 @@ func (span *Span) IsRoot() bool {
 func (span *Span) Context() tracing.SpanContext {
 	return NewSpanContext()
 }
+
+// release notifies the tracer that the span is no longer active, only once
+func (span *Span) release() {
+	span.once.Do(func() {
+		if span.onFinish != nil {
+			span.onFinish(span)
+		}
+	})
+}
@@ -14,3 +14,39 @@ func NewSpanContext() *SpanContext {
 func (spanCtx *SpanContext) RawContext() interface{} {
 	return nil
 }
+
+// TraceID returns identifier of the trace or empty string if there is none
+func (spanCtx *SpanContext) TraceID() string {
+	return ""
+}
+
+// SpanID returns identifier of the span or empty string if there is none
+func (spanCtx *SpanContext) SpanID() string {
+	return ""
+}
+
+// ParentSpanID returns identifier of the parent span or empty string if there is none
+func (spanCtx *SpanContext) ParentSpanID() string {
+	return ""
+}
+
+// IsSampled tells whether the trace is going to be reported
+func (spanCtx *SpanContext) IsSampled() bool {
+	return false
+}
+
+// IsDebug tells whether the trace is forcibly sampled
+func (spanCtx *SpanContext) IsDebug() bool {
+	return false
+}
+
+// IsValid tells whether the context carries a well-formed trace that can be continued
+func (spanCtx *SpanContext) IsValid() bool {
+	return false
+}
+
+// IsEmpty tells whether the context carries nothing at all, as opposed to an invalid context
+// extracted from a carrier with malformed headers
+func (spanCtx *SpanContext) IsEmpty() bool {
+	return true
+}
@@ -1,15 +1,20 @@
 package noop
 
 import (
+	"sync"
+
 	"github.com/Vinelab/tracing-go"
+	"github.com/Vinelab/tracing-go/support/stack"
 )
 
 // Tracer is the tracing implementation for Zipkin. It should be initialized using NewTracer method.
 type Tracer struct {
 	extractionFormats map[string]tracing.Extractor
 	injectionFormats  map[string]tracing.Injector
-	rootSpan          tracing.Span
-	currentSpan       tracing.Span
+	activeSpans       stack.Spans
+
+	mu       sync.Mutex
+	rootSpan tracing.Span
 }
 
 // NewTracer returns a new Zipkin tracer.
@@ -23,28 +28,41 @@ func NewTracer() *Tracer {
 //
 // If parent context does not contain a trace, a new trace will be implicitly created.
 // Use EmptySpanContext to supply empty (nil) context.
-func (tracer *Tracer) StartSpan(name string, spanCtx tracing.SpanContext) tracing.Span {
+//
+// Options such as StartTime may be supplied to customize the span.
+func (tracer *Tracer) StartSpan(name string, spanCtx tracing.SpanContext, opts ...tracing.StartSpanOption) tracing.Span {
+	options := tracing.NewStartSpanOptions(opts...)
+
+	tracer.mu.Lock()
 	var span *Span
-	if tracer.rootSpan != nil {
+	if tracer.rootSpan != nil || options.Detached {
 		span = NewSpan(false)
 	} else {
 		span = NewSpan(true)
 		tracer.rootSpan = span
 	}
+	tracer.mu.Unlock()
 
-	tracer.currentSpan = span
+	// Finishing the span restores its parent as the current span
+	if !options.Detached {
+		span.onFinish = tracer.deactivate
+		tracer.activeSpans.Push(span)
+	}
 
 	return span
 }
 
 // RootSpan retrieves the root span of the service
 func (tracer *Tracer) RootSpan() tracing.Span {
+	tracer.mu.Lock()
+	defer tracer.mu.Unlock()
+
 	return tracer.rootSpan
 }
 
-// CurrentSpan retrieves the most recently activated span.
+// CurrentSpan retrieves the innermost span that has not finished yet.
 func (tracer *Tracer) CurrentSpan() tracing.Span {
-	return tracer.currentSpan
+	return tracer.activeSpans.Top()
 }
 
 // UUID retrieves unique identifier associated with a root span
@@ -57,6 +75,11 @@ func (tracer *Tracer) EmptySpanContext() tracing.SpanContext {
 	return NewSpanContext()
 }
 
+// BuildSpanContext returns empty span context since noop tracer does not track identifiers
+func (tracer *Tracer) BuildSpanContext(traceID string, spanID string, sampled bool) (tracing.SpanContext, error) {
+	return NewSpanContext(), nil
+}
+
 // Extract deserializes span context from from a given carrier using the format descriptor
 // that tells tracer how to decode it from the carrier parameters
 func (tracer *Tracer) Extract(carrier interface{}, format string) (tracing.SpanContext, error) {
@@ -85,11 +108,23 @@ func (tracer *Tracer) RegisterInjectionFormat(format string, injector tracing.In
 	//
 }
 
+// Stats retrieves self-telemetry of the tracer and its reporter
+func (tracer *Tracer) Stats() tracing.Stats {
+	return tracing.Stats{}
+}
+
 // Flush may flush any pending spans to the transport and reset the state of the tracer.
 // Make sure this method is always called after the request is finished.
 func (tracer *Tracer) Flush() {
+	tracer.mu.Lock()
+	rootSpan := tracer.rootSpan
 	tracer.rootSpan = nil
-	tracer.currentSpan = nil
+	tracer.mu.Unlock()
+
+	// Spans of other traces (i.e. concurrent requests) remain active
+	if rootSpan != nil {
+		tracer.activeSpans.ResetTrace(rootSpan.Context().TraceID())
+	}
 }
 
 // Close does a clean shutdown of the reporter, sending any traces that may be buffered in memory.
@@ -101,3 +136,8 @@ func (tracer *Tracer) Flush() {
 func (tracer *Tracer) Close() error {
 	return nil
 }
+
+// deactivate removes finished span from the stack of active spans
+func (tracer *Tracer) deactivate(span *Span) {
+	tracer.activeSpans.Remove(span)
+}
@@ -0,0 +1,9 @@
+package otel
+
+// Adapters of the carriers the default formats are documented to accept
+import (
+	_ "github.com/Vinelab/tracing-go/carriers/amqp"
+	_ "github.com/Vinelab/tracing-go/carriers/aws"
+	_ "github.com/Vinelab/tracing-go/carriers/nats"
+	_ "github.com/Vinelab/tracing-go/carriers/pubsub"
+)
@@ -0,0 +1,210 @@
+package otel
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sort"
+
+	"github.com/Vinelab/tracing-go"
+	"github.com/Vinelab/tracing-go/carriers"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// MaxMessageAttributes is the maximum number of attributes SQS and SNS accept per message
+const MaxMessageAttributes = 10
+
+var (
+	// ErrTooManyMessageAttributes is returned if the message has no room left for the trace context attribute
+	ErrTooManyMessageAttributes = errors.New("too many message attributes to inject trace context")
+)
+
+// Extractor deserializes span context using OpenTelemetry propagator from any carrier
+// that can be adapted to tracing.TextMapReader (see carriers.NewReader)
+type Extractor struct {
+	propagator propagation.TextMapPropagator
+}
+
+// NewExtractor returns the Extractor reading trace context with given propagator
+func NewExtractor(propagator propagation.TextMapPropagator) *Extractor {
+	return &Extractor{propagator: propagator}
+}
+
+// Extract deserializes SpanContext from the carrier
+func (extractor *Extractor) Extract(carrier interface{}) (tracing.SpanContext, error) {
+	reader, err := carriers.NewReader(carrier)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := extractor.propagator.Extract(context.Background(), readerCarrier{reader})
+	return NewSpanContext(trace.SpanContextFromContext(ctx)), nil
+}
+
+// Injector serializes span context using OpenTelemetry propagator into any carrier
+// that can be adapted to tracing.TextMapWriter (see carriers.NewWriter)
+type Injector struct {
+	propagator propagation.TextMapPropagator
+	// limit is the maximum number of entries the carrier may hold, zero means no limit
+	limit int
+}
+
+// NewInjector returns the Injector writing trace context with given propagator
+func NewInjector(propagator propagation.TextMapPropagator) *Injector {
+	return &Injector{propagator: propagator}
+}
+
+// Inject serialises given SpanContext into the carrier
+func (injector *Injector) Inject(spanCtx tracing.SpanContext, carrier interface{}) error {
+	rawCtx := spanCtx.RawContext()
+
+	otelCtx, ok := rawCtx.(trace.SpanContext)
+	if !ok {
+		log.Fatalf("Expected %T, got %T", trace.SpanContext{}, rawCtx)
+	}
+
+	writer, err := carriers.NewWriter(carrier)
+	if err != nil {
+		return err
+	}
+
+	ctx := trace.ContextWithSpanContext(context.Background(), otelCtx)
+	if injector.limit > 0 {
+		return injector.injectLimited(ctx, writer)
+	}
+
+	injector.propagator.Inject(ctx, writerCarrier{writer})
+
+	return nil
+}
+
+// optionalFields are propagated fields the trace can be continued without, baggage is dropped first
+var optionalFields = []string{"tracestate", "baggage"}
+
+// injectLimited writes the fields essential to continue the trace (i.e. traceparent) before
+// tracestate and baggage as long as the carrier stays within the limit, the remaining fields are dropped
+func (injector *Injector) injectLimited(ctx context.Context, writer tracing.TextMapWriter) error {
+	fields := propagation.MapCarrier{}
+	injector.propagator.Inject(ctx, fields)
+
+	count := 0
+	if reader, ok := writer.(tracing.TextMapReader); ok {
+		for _, key := range reader.Keys() {
+			if _, ok := fields[key]; !ok {
+				count++
+			}
+		}
+	}
+
+	written := 0
+	for _, key := range fieldsByPriority(injector.propagator.Fields()) {
+		value, ok := fields[key]
+		if !ok {
+			continue
+		}
+
+		if count >= injector.limit {
+			if written == 0 {
+				return ErrTooManyMessageAttributes
+			}
+			break
+		}
+
+		writer.Set(key, value)
+		count++
+		written++
+	}
+
+	return nil
+}
+
+// fieldsByPriority orders the fields essential ones first, since composite propagators list them in random order
+func fieldsByPriority(fields []string) []string {
+	rank := func(field string) int {
+		for i, optional := range optionalFields {
+			if field == optional {
+				return i + 1
+			}
+		}
+		return 0
+	}
+
+	sorted := append([]string(nil), fields...)
+	sort.Slice(sorted, func(i, j int) bool {
+		if rank(sorted[i]) != rank(sorted[j]) {
+			return rank(sorted[i]) < rank(sorted[j])
+		}
+		return sorted[i] < sorted[j]
+	})
+
+	return sorted
+}
+
+// NewTextMapExtractor returns the Extractor for map[string]string carrier
+func NewTextMapExtractor(propagator propagation.TextMapPropagator) *Extractor {
+	return NewExtractor(propagator)
+}
+
+// NewTextMapInjector returns the Injector for *map[string]string carrier
+func NewTextMapInjector(propagator propagation.TextMapPropagator) *Injector {
+	return NewInjector(propagator)
+}
+
+// NewHTTPExtractor returns the Extractor for *http.Request carrier
+func NewHTTPExtractor(propagator propagation.TextMapPropagator) *Extractor {
+	return NewExtractor(propagator)
+}
+
+// NewHTTPInjector returns the Injector for *http.Request carrier
+func NewHTTPInjector(propagator propagation.TextMapPropagator) *Injector {
+	return NewInjector(propagator)
+}
+
+// NewAMQPExtractor returns the Extractor for *amqp.Delivery carrier
+func NewAMQPExtractor(propagator propagation.TextMapPropagator) *Extractor {
+	return NewExtractor(propagator)
+}
+
+// NewAMQPInjector returns the Injector for *amqp.Publishing carrier
+func NewAMQPInjector(propagator propagation.TextMapPropagator) *Injector {
+	return NewInjector(propagator)
+}
+
+// NewGooglePubSubExtractor returns the Extractor for *pubsub.Message carrier
+func NewGooglePubSubExtractor(propagator propagation.TextMapPropagator) *Extractor {
+	return NewExtractor(propagator)
+}
+
+// NewGooglePubSubInjector returns the Injector for *pubsub.Message carrier
+func NewGooglePubSubInjector(propagator propagation.TextMapPropagator) *Injector {
+	return NewInjector(propagator)
+}
+
+// NewMessageAttributesInjector returns the Injector for SQS or SNS message attributes, dropping the least
+// essential fields when the message would exceed MaxMessageAttributes
+func NewMessageAttributesInjector(propagator propagation.TextMapPropagator) *Injector {
+	return &Injector{propagator: propagator, limit: MaxMessageAttributes}
+}
+
+// readerCarrier adapts tracing.TextMapReader to propagation.TextMapCarrier, writes are ignored
+type readerCarrier struct {
+	tracing.TextMapReader
+}
+
+func (carrier readerCarrier) Set(key string, value string) {
+	//
+}
+
+// writerCarrier adapts tracing.TextMapWriter to propagation.TextMapCarrier, nothing can be read from it
+type writerCarrier struct {
+	tracing.TextMapWriter
+}
+
+func (carrier writerCarrier) Get(key string) string {
+	return ""
+}
+
+func (carrier writerCarrier) Keys() []string {
+	return nil
+}
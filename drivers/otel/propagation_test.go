@@ -0,0 +1,124 @@
+package otel
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/Vinelab/tracing-go"
+	"github.com/Vinelab/tracing-go/carriers"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sns"
+	snstypes "github.com/aws/aws-sdk-go-v2/service/sns/types"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	sqstypes "github.com/aws/aws-sdk-go-v2/service/sqs/types"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func testSpanContext(t *testing.T) *SpanContext {
+	t.Helper()
+
+	state, err := trace.ParseTraceState("vendor=value")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return NewSpanContext(trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{0x01, 0x02},
+		SpanID:     trace.SpanID{0x03},
+		TraceFlags: trace.FlagsSampled,
+		TraceState: state,
+	}))
+}
+
+func messageCarriers(existing int) map[string]interface{} {
+	sqsAttributes := make(map[string]sqstypes.MessageAttributeValue)
+	snsAttributes := make(map[string]snstypes.MessageAttributeValue)
+	for i := 0; i < existing; i++ {
+		key := fmt.Sprintf("attribute-%d", i)
+		sqsAttributes[key] = sqstypes.MessageAttributeValue{DataType: aws.String("String"), StringValue: aws.String("value")}
+		snsAttributes[key] = snstypes.MessageAttributeValue{DataType: aws.String("String"), StringValue: aws.String("value")}
+	}
+
+	return map[string]interface{}{
+		"sqs": &sqs.SendMessageInput{MessageAttributes: sqsAttributes},
+		"sns": &sns.PublishInput{MessageAttributes: snsAttributes},
+	}
+}
+
+func TestMessageAttributesInjector(t *testing.T) {
+	cases := []struct {
+		existing   int
+		tracestate bool
+		err        error
+	}{
+		{existing: 0, tracestate: true},
+		{existing: 8, tracestate: true},
+		{existing: 9},
+		{existing: 10, err: ErrTooManyMessageAttributes},
+	}
+
+	injector := NewMessageAttributesInjector(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	extractor := NewExtractor(propagation.TraceContext{})
+	spanCtx := testSpanContext(t)
+
+	for _, c := range cases {
+		for name, carrier := range messageCarriers(c.existing) {
+			err := injector.Inject(spanCtx, carrier)
+			if err != c.err {
+				t.Errorf("%s with %d attributes: expected error %v, got %v", name, c.existing, c.err, err)
+			}
+
+			reader, err := carriers.NewReader(carrier)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			keys := reader.Keys()
+			if len(keys) > MaxMessageAttributes {
+				t.Errorf("%s with %d attributes: expected at most %d attributes, got %d", name, c.existing, MaxMessageAttributes, len(keys))
+			}
+			if c.err != nil {
+				if len(keys) != c.existing {
+					t.Errorf("%s with %d attributes: expected nothing injected, got %v", name, c.existing, keys)
+				}
+				continue
+			}
+
+			if hasState := reader.Get("tracestate") != ""; hasState != c.tracestate {
+				t.Errorf("%s with %d attributes: expected tracestate %v, got %v", name, c.existing, c.tracestate, hasState)
+			}
+
+			extracted, err := extractor.Extract(carrier)
+			if err != nil || extracted.TraceID() != spanCtx.TraceID() || extracted.SpanID() != spanCtx.SpanID() {
+				t.Errorf("%s with %d attributes: expected extractable context, got %v (%v)", name, c.existing, extracted, err)
+			}
+		}
+	}
+}
+
+func TestInjectorWithoutLimit(t *testing.T) {
+	carrier := map[string]string{}
+	for i := 0; i < MaxMessageAttributes; i++ {
+		carrier[fmt.Sprintf("header-%d", i)] = "value"
+	}
+
+	injector := NewTextMapInjector(propagation.TraceContext{})
+	if err := injector.Inject(testSpanContext(t), carrier); err != nil {
+		t.Fatal(err)
+	}
+
+	var reader tracing.TextMapReader = carriers.TextMap(carrier)
+	if reader.Get("traceparent") == "" || reader.Get("tracestate") == "" {
+		t.Errorf("expected all fields injected, got %v", carrier)
+	}
+}
+
+func TestFieldsByPriority(t *testing.T) {
+	fields := fieldsByPriority([]string{"baggage", "tracestate", "x-b3-traceid", "traceparent"})
+	expected := []string{"traceparent", "x-b3-traceid", "tracestate", "baggage"}
+
+	if fmt.Sprint(fields) != fmt.Sprint(expected) {
+		t.Errorf("expected %v, got %v", expected, fields)
+	}
+}
@@ -0,0 +1,121 @@
+package otel
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Vinelab/tracing-go"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Span encapsulates the state of logical operation it represents
+type Span struct {
+	rawSpan   trace.Span
+	isRoot    bool
+	startTime time.Time
+	onFinish  func(span *Span)
+	once      sync.Once
+}
+
+// NewSpan returns a new Span
+func NewSpan(rawSpan trace.Span, isRoot bool, startTime time.Time) *Span {
+	return &Span{rawSpan: rawSpan, isRoot: isRoot, startTime: startTime}
+}
+
+// SetName sets (overrides) the string name for the logical operation this span represents.
+func (span *Span) SetName(name string) {
+	span.rawSpan.SetName(name)
+}
+
+// Tag give your span context for search, viewing and analysis. For example,
+// a key "your_app.version" would let you lookup spans by version.
+//
+// Tags are recorded as string attributes, error tag also sets the error status of the span.
+func (span *Span) Tag(key string, value string) {
+	span.rawSpan.SetAttributes(attribute.String(key, value))
+
+	if key == "error" {
+		description := value
+		if description == "true" {
+			description = ""
+		}
+
+		span.rawSpan.SetStatus(codes.Error, description)
+	}
+}
+
+// Finish notifies that operation has finished. Span duration is derived by subtracting the start
+// timestamp from this, and set when appropriate.
+func (span *Span) Finish() {
+	span.rawSpan.End()
+	span.release()
+}
+
+// FinishAt notifies that operation has finished at the given timestamp.
+func (span *Span) FinishAt(timestamp time.Time) {
+	span.rawSpan.End(trace.WithTimestamp(timestamp))
+	span.release()
+}
+
+// FinishWithDuration notifies that operation has finished after the given duration since the start.
+func (span *Span) FinishWithDuration(duration time.Duration) {
+	span.FinishAt(span.startTime.Add(duration))
+}
+
+// Annotate associates an event that explains latency with a timestamp.
+func (span *Span) Annotate(message string) {
+	span.rawSpan.AddEvent(message)
+}
+
+// Log stores structured data as an event named after the "event" field (defaults to "log")
+// with the rest of fields recorded as its attributes.
+func (span *Span) Log(fields map[string]string) {
+	span.LogAt(time.Now(), fields)
+}
+
+// LogAt stores structured data with the given timestamp. Use it for backfilled events
+func (span *Span) LogAt(timestamp time.Time, fields map[string]string) {
+	name := "log"
+	attributes := make([]attribute.KeyValue, 0, len(fields))
+	for key, value := range fields {
+		if key == "event" {
+			name = value
+			continue
+		}
+
+		attributes = append(attributes, attribute.String(key, value))
+	}
+
+	sort.Slice(attributes, func(i, j int) bool {
+		return attributes[i].Key < attributes[j].Key
+	})
+
+	span.rawSpan.AddEvent(name, trace.WithTimestamp(timestamp), trace.WithAttributes(attributes...))
+}
+
+// IsRoot tells whether the span is a root span
+func (span *Span) IsRoot() bool {
+	return span.isRoot
+}
+
+// Context retrieves SpanContext for this Span
+func (span *Span) Context() tracing.SpanContext {
+	return NewSpanContext(span.rawSpan.SpanContext())
+}
+
+// Unwrap returns the underlying OpenTelemetry span
+func (span *Span) Unwrap() trace.Span {
+	return span.rawSpan
+}
+
+// release notifies the tracer that the span is no longer active, only once
+func (span *Span) release() {
+	span.once.Do(func() {
+		if span.onFinish != nil {
+			span.onFinish(span)
+		}
+	})
+}
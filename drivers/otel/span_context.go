@@ -0,0 +1,70 @@
+package otel
+
+import (
+	"go.opentelemetry.io/otel/trace"
+)
+
+// SpanContext holds the context of a Span. It should be initialized using NewSpanContext method.
+type SpanContext struct {
+	rawCtx interface{}
+}
+
+// NewSpanContext returns a new SpanContext
+func NewSpanContext(rawCtx interface{}) *SpanContext {
+	return &SpanContext{rawCtx: rawCtx}
+}
+
+// RawContext returns underlying (original) span context.
+func (spanCtx *SpanContext) RawContext() interface{} {
+	return spanCtx.rawCtx
+}
+
+// TraceID returns identifier of the trace or empty string if there is none
+func (spanCtx *SpanContext) TraceID() string {
+	otelCtx := spanCtx.model()
+	if !otelCtx.TraceID().IsValid() {
+		return ""
+	}
+
+	return otelCtx.TraceID().String()
+}
+
+// SpanID returns identifier of the span or empty string if there is none
+func (spanCtx *SpanContext) SpanID() string {
+	otelCtx := spanCtx.model()
+	if !otelCtx.SpanID().IsValid() {
+		return ""
+	}
+
+	return otelCtx.SpanID().String()
+}
+
+// ParentSpanID always returns empty string because OpenTelemetry does not propagate parent span ID
+func (spanCtx *SpanContext) ParentSpanID() string {
+	return ""
+}
+
+// IsSampled tells whether the trace is sampled
+func (spanCtx *SpanContext) IsSampled() bool {
+	return spanCtx.model().IsSampled()
+}
+
+// IsDebug always returns false because OpenTelemetry has no notion of debug flag
+func (spanCtx *SpanContext) IsDebug() bool {
+	return false
+}
+
+// IsValid tells whether the context holds a trace that can be continued
+func (spanCtx *SpanContext) IsValid() bool {
+	return spanCtx.model().IsValid()
+}
+
+// IsEmpty tells whether the context was created by EmptySpanContext
+func (spanCtx *SpanContext) IsEmpty() bool {
+	return spanCtx.rawCtx == nil
+}
+
+func (spanCtx *SpanContext) model() trace.SpanContext {
+	otelCtx, _ := spanCtx.rawCtx.(trace.SpanContext)
+	return otelCtx
+}
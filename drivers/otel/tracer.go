@@ -0,0 +1,397 @@
+package otel
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Vinelab/tracing-go"
+	"github.com/Vinelab/tracing-go/formats"
+	"github.com/Vinelab/tracing-go/support/stack"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// DefaultInstrumentationName is the name of instrumentation scope spans are reported with by default
+	DefaultInstrumentationName = "github.com/Vinelab/tracing-go"
+)
+
+var (
+	// ErrTracerProviderRequired is returned if TracerProvider option is missing
+	ErrTracerProviderRequired = errors.New("tracer provider is required")
+)
+
+// Tracer is the tracing implementation backed by OpenTelemetry TracerProvider (i.e. the one of OpenTelemetry SDK).
+// It should be initialized using NewTracer method.
+type Tracer struct {
+	provider          trace.TracerProvider
+	tracing           trace.Tracer
+	extractionFormats map[string]tracing.Extractor
+	injectionFormats  map[string]tracing.Injector
+	activeSpans       stack.Spans
+	spansStarted      uint64
+	spansFinished     uint64
+
+	mu       sync.Mutex
+	rootSpan tracing.Span
+	uuid     string
+}
+
+// TracerOptions is a configuration container to setup the Tracer.
+type TracerOptions struct {
+	// TracerProvider creates spans and exports them (i.e. sdktrace.NewTracerProvider)
+	// Required
+	TracerProvider trace.TracerProvider
+	// InstrumentationName is the name of instrumentation scope spans are reported with
+	// Defaults to DefaultInstrumentationName
+	InstrumentationName string
+	// Propagator serializes span context for all the formats (formats.TextMap, formats.HTTP, etc.)
+	// Defaults to W3C Trace Context (propagation.TraceContext)
+	Propagator propagation.TextMapPropagator
+}
+
+// NewTracer returns a new OpenTelemetry tracer.
+func NewTracer(opt TracerOptions) (*Tracer, error) {
+	if opt.TracerProvider == nil {
+		return nil, ErrTracerProviderRequired
+	}
+
+	if opt.InstrumentationName == "" {
+		opt.InstrumentationName = DefaultInstrumentationName
+	}
+
+	if opt.Propagator == nil {
+		opt.Propagator = propagation.TraceContext{}
+	}
+
+	return &Tracer{
+		provider:          opt.TracerProvider,
+		tracing:           opt.TracerProvider.Tracer(opt.InstrumentationName),
+		extractionFormats: registerDefaultExtractionFormats(opt.Propagator),
+		injectionFormats:  registerDefaultInjectionFormats(opt.Propagator),
+	}, nil
+}
+
+// StartSpan starts a new span based on a parent trace context. The context may come either from
+// external source (extracted from HTTP request, AMQP message, etc., see Extract method)
+// or received from another span in the service.
+//
+// If parent context does not contain a trace, a new trace will be implicitly created.
+// Use EmptySpanContext to supply empty (nil) context.
+//
+// Options such as StartTime may be supplied to customize the span.
+func (tracer *Tracer) StartSpan(name string, spanCtx tracing.SpanContext, opts ...tracing.StartSpanOption) tracing.Span {
+	options := tracing.NewStartSpanOptions(opts...)
+
+	startTime := options.StartTime
+	if startTime.IsZero() {
+		startTime = time.Now()
+	}
+
+	spanOptions := []trace.SpanStartOption{trace.WithTimestamp(startTime)}
+	if options.Kind != "" {
+		spanOptions = append(spanOptions, trace.WithSpanKind(spanKind(options.Kind)))
+	}
+
+	// OpenTelemetry has no notion of follows-from references, so the first one becomes
+	// the parent unless it was given explicitly. The rest are recorded as links.
+	parent, _ := spanCtx.RawContext().(trace.SpanContext)
+	references := options.References
+	if !parent.IsValid() {
+		for i, reference := range references {
+			referenced, ok := reference.SpanContext.RawContext().(trace.SpanContext)
+			if reference.Type == tracing.FollowsFromReference && ok && referenced.IsValid() {
+				parent = referenced
+				references = append(references[:i:i], references[i+1:]...)
+				break
+			}
+		}
+	}
+
+	if len(references) < len(options.References) {
+		spanOptions = append(spanOptions, trace.WithAttributes(
+			attribute.String("reference_type", string(tracing.FollowsFromReference)),
+		))
+	}
+	spanOptions = append(spanOptions, trace.WithLinks(links(references)...))
+
+	ctx := context.Background()
+	if parent.IsValid() {
+		ctx = trace.ContextWithSpanContext(ctx, parent)
+	} else {
+		spanOptions = append(spanOptions, trace.WithNewRoot())
+	}
+
+	var rawSpan trace.Span
+	if !parent.IsValid() && options.Sampled != nil && !*options.Sampled {
+		// Samplers cannot be told the decision, so the new trace is propagated without being recorded
+		ctx = trace.ContextWithSpanContext(ctx, trace.NewSpanContext(trace.SpanContextConfig{
+			TraceID: newTraceID(),
+			SpanID:  newSpanID(),
+		}))
+		rawSpan = trace.SpanFromContext(ctx)
+	} else {
+		_, rawSpan = tracer.tracing.Start(ctx, name, spanOptions...)
+	}
+
+	tracer.mu.Lock()
+	var span *Span
+	if tracer.rootSpan != nil || options.Detached {
+		span = NewSpan(rawSpan, false, startTime)
+	} else {
+		span = NewSpan(rawSpan, true, startTime)
+		tracer.rootSpan = span
+
+		value, err := uuid.NewUUID()
+		if err != nil {
+			panic(err)
+		}
+		tracer.uuid = value.String()
+		span.Tag("uuid", tracer.uuid)
+	}
+	tracer.mu.Unlock()
+
+	// Finishing the span restores its parent as the current span
+	if !options.Detached {
+		span.onFinish = tracer.deactivate
+		tracer.activeSpans.Push(span)
+	}
+	atomic.AddUint64(&tracer.spansStarted, 1)
+
+	return span
+}
+
+// RootSpan retrieves the root span of the service
+func (tracer *Tracer) RootSpan() tracing.Span {
+	tracer.mu.Lock()
+	defer tracer.mu.Unlock()
+
+	return tracer.rootSpan
+}
+
+// CurrentSpan retrieves the innermost span that has not finished yet.
+func (tracer *Tracer) CurrentSpan() tracing.Span {
+	return tracer.activeSpans.Top()
+}
+
+// UUID retrieves unique identifier associated with a root span
+func (tracer *Tracer) UUID() string {
+	tracer.mu.Lock()
+	defer tracer.mu.Unlock()
+
+	return tracer.uuid
+}
+
+// EmptySpanContext return empty span context for creating spans
+func (tracer *Tracer) EmptySpanContext() tracing.SpanContext {
+	return NewSpanContext(nil)
+}
+
+// BuildSpanContext returns span context of the remote span with given hex encoded identifiers.
+// 64 bit trace IDs are left-padded with zeros.
+func (tracer *Tracer) BuildSpanContext(traceID string, spanID string, sampled bool) (tracing.SpanContext, error) {
+	if len(traceID) < 32 {
+		traceID = strings.Repeat("0", 32-len(traceID)) + traceID
+	}
+
+	otelTraceID, err := trace.TraceIDFromHex(traceID)
+	if err != nil {
+		return nil, err
+	}
+
+	otelSpanID, err := trace.SpanIDFromHex(spanID)
+	if err != nil {
+		return nil, err
+	}
+
+	var flags trace.TraceFlags
+	if sampled {
+		flags = trace.FlagsSampled
+	}
+
+	return NewSpanContext(trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    otelTraceID,
+		SpanID:     otelSpanID,
+		TraceFlags: flags,
+		Remote:     true,
+	})), nil
+}
+
+// Extract deserializes span context from from a given carrier using the format descriptor
+// that tells tracer how to decode it from the carrier parameters
+func (tracer *Tracer) Extract(carrier interface{}, format string) (tracing.SpanContext, error) {
+	extractor, ok := tracer.extractionFormats[format]
+	if !ok {
+		return nil, tracing.NewUnregisteredFormatError("No extractor registered for format", format)
+	}
+
+	return extractor.Extract(carrier)
+}
+
+// Inject implicitly serializes current span context using the format descriptor that
+// tells how to encode trace info in the carrier parameters
+func (tracer *Tracer) Inject(carrier interface{}, format string) error {
+	span := tracer.CurrentSpan()
+	if span == nil {
+		return nil
+	}
+
+	return tracer.InjectContext(carrier, format, span.Context())
+}
+
+// InjectContext serializes specified span context into a given carrier using the format descriptor
+// that tells how to encode trace info in the carrier parameters
+func (tracer *Tracer) InjectContext(carrier interface{}, format string, spanCtx tracing.SpanContext) error {
+	injector, ok := tracer.injectionFormats[format]
+	if !ok {
+		return tracing.NewUnregisteredFormatError("No injector registered for format", format)
+	}
+
+	return injector.Inject(spanCtx, carrier)
+}
+
+// RegisterExtractionFormat register extractor implementation for given format string
+func (tracer *Tracer) RegisterExtractionFormat(format string, extractor tracing.Extractor) {
+	tracer.extractionFormats[format] = extractor
+}
+
+// RegisterInjectionFormat register injector implementation for given format string
+func (tracer *Tracer) RegisterInjectionFormat(format string, injector tracing.Injector) {
+	tracer.injectionFormats[format] = injector
+}
+
+// Stats retrieves self-telemetry of the tracer. Export related stats are left empty
+// because the tracer provider does not expose them.
+func (tracer *Tracer) Stats() tracing.Stats {
+	return tracing.Stats{
+		SpansStarted:  atomic.LoadUint64(&tracer.spansStarted),
+		SpansFinished: atomic.LoadUint64(&tracer.spansFinished),
+	}
+}
+
+// Flush may flush any pending spans to the transport and reset the state of the tracer.
+// Make sure this method is always called after the request is finished.
+func (tracer *Tracer) Flush() {
+	tracer.mu.Lock()
+	rootSpan := tracer.rootSpan
+	tracer.rootSpan = nil
+	tracer.uuid = ""
+	tracer.mu.Unlock()
+
+	// Spans of other traces (i.e. concurrent requests) remain active
+	if rootSpan != nil {
+		tracer.activeSpans.ResetTrace(rootSpan.Context().TraceID())
+	}
+}
+
+// Close does a clean shutdown of the tracer provider (if it supports it), sending any traces
+// that may be buffered in memory.
+//
+// It goes without saying, but you cannot send anymore spans after calling Close,
+// so you should only run this once during the lifecycle of the program.
+func (tracer *Tracer) Close() error {
+	if provider, ok := tracer.provider.(interface{ Shutdown(context.Context) error }); ok {
+		return provider.Shutdown(context.Background())
+	}
+
+	return nil
+}
+
+// deactivate removes finished span from the stack of active spans
+func (tracer *Tracer) deactivate(span *Span) {
+	tracer.activeSpans.Remove(span)
+	atomic.AddUint64(&tracer.spansFinished, 1)
+}
+
+// links converts references created by this driver into OpenTelemetry links
+func links(references []tracing.Reference) []trace.Link {
+	result := make([]trace.Link, 0, len(references))
+	for _, reference := range references {
+		spanCtx, ok := reference.SpanContext.RawContext().(trace.SpanContext)
+		if !ok || !spanCtx.IsValid() {
+			continue
+		}
+
+		attributes := make([]attribute.KeyValue, 0, len(reference.Attributes)+1)
+		attributes = append(attributes, attribute.String("reference_type", string(reference.Type)))
+		for key, value := range reference.Attributes {
+			attributes = append(attributes, attribute.String(key, value))
+		}
+
+		result = append(result, trace.Link{SpanContext: spanCtx, Attributes: attributes})
+	}
+
+	return result
+}
+
+func registerDefaultExtractionFormats(propagator propagation.TextMapPropagator) map[string]tracing.Extractor {
+	extractionFormats := make(map[string]tracing.Extractor)
+
+	extractionFormats[formats.TextMap] = NewTextMapExtractor(propagator)
+	extractionFormats[formats.HTTP] = NewHTTPExtractor(propagator)
+	extractionFormats[formats.AMQP] = NewAMQPExtractor(propagator)
+	extractionFormats[formats.GooglePubSub] = NewGooglePubSubExtractor(propagator)
+	extractionFormats[formats.NATS] = NewExtractor(propagator)
+	extractionFormats[formats.SQS] = NewExtractor(propagator)
+	extractionFormats[formats.SNS] = NewExtractor(propagator)
+	extractionFormats[formats.W3C] = NewExtractor(propagation.TraceContext{})
+
+	return extractionFormats
+}
+
+func registerDefaultInjectionFormats(propagator propagation.TextMapPropagator) map[string]tracing.Injector {
+	injectionFormats := make(map[string]tracing.Injector)
+
+	injectionFormats[formats.TextMap] = NewTextMapInjector(propagator)
+	injectionFormats[formats.HTTP] = NewHTTPInjector(propagator)
+	injectionFormats[formats.AMQP] = NewAMQPInjector(propagator)
+	injectionFormats[formats.GooglePubSub] = NewGooglePubSubInjector(propagator)
+	injectionFormats[formats.NATS] = NewInjector(propagator)
+	injectionFormats[formats.SQS] = NewMessageAttributesInjector(propagator)
+	injectionFormats[formats.SNS] = NewMessageAttributesInjector(propagator)
+	injectionFormats[formats.W3C] = NewInjector(propagation.TraceContext{})
+
+	return injectionFormats
+}
+
+func spanKind(kind tracing.SpanKind) trace.SpanKind {
+	switch kind {
+	case tracing.SpanKindClient:
+		return trace.SpanKindClient
+	case tracing.SpanKindServer:
+		return trace.SpanKindServer
+	case tracing.SpanKindProducer:
+		return trace.SpanKindProducer
+	case tracing.SpanKindConsumer:
+		return trace.SpanKindConsumer
+	}
+
+	return trace.SpanKindInternal
+}
+
+// newTraceID generates a random trace identifier for traces that are not recorded
+func newTraceID() trace.TraceID {
+	var id trace.TraceID
+	for id == (trace.TraceID{}) {
+		_, _ = rand.Read(id[:])
+	}
+
+	return id
+}
+
+// newSpanID generates a random span identifier for spans that are not recorded
+func newSpanID() trace.SpanID {
+	var id trace.SpanID
+	for id == (trace.SpanID{}) {
+		_, _ = rand.Read(id[:])
+	}
+
+	return id
+}
@@ -0,0 +1,328 @@
+package stdout
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Vinelab/tracing-go"
+	"github.com/openzipkin/zipkin-go/model"
+)
+
+// Format tells how spans are written
+type Format int
+
+const (
+	// JSON writes every finished span as a line of Zipkin v2 JSON
+	JSON Format = iota
+	// Tree writes every trace as a human-readable tree of spans indented by depth,
+	// once its local root span has finished
+	Tree
+)
+
+const (
+	// MaxBufferedSpans controls the maximum number of spans of a single trace held
+	// in memory by the Tree format before the trace is written as is
+	MaxBufferedSpans = 10000
+	// MaxBufferedTraces controls the maximum number of traces held in memory by the Tree format,
+	// the least recently updated trace is written as is to make room for a new one
+	MaxBufferedTraces = 1000
+	// TreeTimeout controls how long the Tree format waits for the root span of a trace since its
+	// last span has finished. Spans finishing after the root span (i.e. detached ones) are written
+	// on their own within the same time
+	TreeTimeout = 10 * time.Second
+)
+
+// bufferedTrace holds finished spans of a trace whose root span has not finished yet
+type bufferedTrace struct {
+	spans     []model.SpanModel
+	updatedAt time.Time
+}
+
+// Reporter writes finished spans to the writer. It should be initialized using NewReporter method.
+type Reporter struct {
+	writer io.Writer
+	format Format
+	pretty bool
+
+	mu          sync.Mutex
+	traces      map[model.TraceID]*bufferedTrace
+	written     map[model.TraceID]time.Time
+	timeout     time.Duration
+	reported    uint64
+	dropped     uint64
+	lastError   string
+	lastErrorAt time.Time
+
+	quit      chan struct{}
+	closeOnce sync.Once
+}
+
+// NewReporter returns a new Reporter
+func NewReporter(writer io.Writer, format Format, pretty bool) *Reporter {
+	rep := &Reporter{
+		writer:  writer,
+		format:  format,
+		pretty:  pretty,
+		traces:  make(map[model.TraceID]*bufferedTrace),
+		written: make(map[model.TraceID]time.Time),
+		timeout: TreeTimeout,
+		quit:    make(chan struct{}),
+	}
+
+	if format == Tree {
+		go rep.loop()
+	}
+
+	return rep
+}
+
+// Send writes the span, or buffers it until the whole trace can be written as a tree
+func (rep *Reporter) Send(span model.SpanModel) {
+	rep.mu.Lock()
+	defer rep.mu.Unlock()
+
+	if rep.format == JSON {
+		rep.write([]model.SpanModel{span}, rep.encodeJSON(span))
+		return
+	}
+
+	now := time.Now()
+
+	// The tree has already been written, so there is nothing to wait for
+	if _, ok := rep.written[span.TraceID]; ok {
+		rep.written[span.TraceID] = now
+		rep.write([]model.SpanModel{span}, encodeTree([]model.SpanModel{span}))
+		return
+	}
+
+	trace, ok := rep.traces[span.TraceID]
+	if !ok {
+		if len(rep.traces) >= MaxBufferedTraces {
+			rep.writeOldest()
+		}
+		trace = &bufferedTrace{}
+		rep.traces[span.TraceID] = trace
+	}
+	trace.spans = append(trace.spans, span)
+	trace.updatedAt = now
+
+	// Root span of the service is tagged with uuid by the tracer and finishes last
+	_, isRoot := span.Tags["uuid"]
+	if !isRoot && len(trace.spans) < MaxBufferedSpans {
+		return
+	}
+
+	delete(rep.traces, span.TraceID)
+	rep.write(trace.spans, encodeTree(trace.spans))
+
+	if isRoot {
+		if len(rep.written) >= MaxBufferedTraces {
+			rep.forgetOldest()
+		}
+		rep.written[span.TraceID] = now
+	}
+}
+
+// Close writes traces that are still buffered and closes the RotatingFile the spans are written to
+func (rep *Reporter) Close() error {
+	rep.closeOnce.Do(func() {
+		close(rep.quit)
+	})
+
+	rep.mu.Lock()
+	defer rep.mu.Unlock()
+
+	for traceID, trace := range rep.traces {
+		delete(rep.traces, traceID)
+		rep.write(trace.spans, encodeTree(trace.spans))
+	}
+
+	// Only files of the driver are closed, writers like os.Stdout are owned by the caller
+	if file, ok := rep.writer.(*RotatingFile); ok {
+		return file.Close()
+	}
+
+	return nil
+}
+
+// Stats retrieves reporter related self-telemetry
+func (rep *Reporter) Stats() tracing.Stats {
+	rep.mu.Lock()
+	defer rep.mu.Unlock()
+
+	queueDepth := 0
+	for _, trace := range rep.traces {
+		queueDepth += len(trace.spans)
+	}
+
+	return tracing.Stats{
+		SpansReported:     rep.reported,
+		SpansDropped:      rep.dropped,
+		QueueDepth:        queueDepth,
+		LastExportError:   rep.lastError,
+		LastExportErrorAt: rep.lastErrorAt,
+	}
+}
+
+// loop periodically writes traces that have been waiting for their root span for too long
+func (rep *Reporter) loop() {
+	ticker := time.NewTicker(rep.timeout / 2)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case now := <-ticker.C:
+			rep.expire(now)
+		case <-rep.quit:
+			return
+		}
+	}
+}
+
+// expire writes traces that were not updated within the timeout as they are
+// and stops waiting for late spans of traces that were written
+func (rep *Reporter) expire(now time.Time) {
+	rep.mu.Lock()
+	defer rep.mu.Unlock()
+
+	deadline := now.Add(-rep.timeout)
+	for traceID, trace := range rep.traces {
+		if trace.updatedAt.Before(deadline) {
+			delete(rep.traces, traceID)
+			rep.write(trace.spans, encodeTree(trace.spans))
+		}
+	}
+
+	for traceID, writtenAt := range rep.written {
+		if writtenAt.Before(deadline) {
+			delete(rep.written, traceID)
+		}
+	}
+}
+
+// writeOldest writes the least recently updated trace as is, the caller must hold the lock
+func (rep *Reporter) writeOldest() {
+	var oldestID model.TraceID
+	var oldest *bufferedTrace
+	for traceID, trace := range rep.traces {
+		if oldest == nil || trace.updatedAt.Before(oldest.updatedAt) {
+			oldestID, oldest = traceID, trace
+		}
+	}
+
+	if oldest != nil {
+		delete(rep.traces, oldestID)
+		rep.write(oldest.spans, encodeTree(oldest.spans))
+	}
+}
+
+// forgetOldest stops waiting for late spans of the least recently written trace, the caller must hold the lock
+func (rep *Reporter) forgetOldest() {
+	var oldestID model.TraceID
+	var oldestAt time.Time
+	for traceID, writtenAt := range rep.written {
+		if oldestAt.IsZero() || writtenAt.Before(oldestAt) {
+			oldestID, oldestAt = traceID, writtenAt
+		}
+	}
+
+	delete(rep.written, oldestID)
+}
+
+func (rep *Reporter) write(spans []model.SpanModel, data []byte) {
+	if _, err := rep.writer.Write(data); err != nil {
+		rep.dropped += uint64(len(spans))
+		rep.lastError = err.Error()
+		rep.lastErrorAt = time.Now()
+		return
+	}
+
+	rep.reported += uint64(len(spans))
+}
+
+func (rep *Reporter) encodeJSON(span model.SpanModel) []byte {
+	data, err := json.Marshal(span)
+	if err != nil {
+		return []byte(fmt.Sprintf("{\"error\":%q}\n", err.Error()))
+	}
+
+	if rep.pretty {
+		buffer := bytes.Buffer{}
+		if json.Indent(&buffer, data, "", "  ") == nil {
+			data = buffer.Bytes()
+		}
+	}
+
+	return append(data, '\n')
+}
+
+// encodeTree renders spans of a trace indented by their depth, children ordered by start time
+func encodeTree(spans []model.SpanModel) []byte {
+	sort.Slice(spans, func(i, j int) bool {
+		return spans[i].Timestamp.Before(spans[j].Timestamp)
+	})
+
+	known := make(map[model.ID]bool, len(spans))
+	for _, span := range spans {
+		known[span.ID] = true
+	}
+
+	children := make(map[model.ID][]model.SpanModel)
+	roots := make([]model.SpanModel, 0, 1)
+	for _, span := range spans {
+		if span.ParentID != nil && known[*span.ParentID] && *span.ParentID != span.ID {
+			children[*span.ParentID] = append(children[*span.ParentID], span)
+		} else {
+			roots = append(roots, span)
+		}
+	}
+
+	buffer := bytes.Buffer{}
+	fmt.Fprintf(&buffer, "Trace %s\n", spans[0].TraceID)
+	for _, root := range roots {
+		encodeNode(&buffer, root, children, 1)
+	}
+
+	return buffer.Bytes()
+}
+
+func encodeNode(buffer *bytes.Buffer, span model.SpanModel, children map[model.ID][]model.SpanModel, depth int) {
+	indent := strings.Repeat("  ", depth)
+
+	fmt.Fprintf(buffer, "%s%s (%s) span=%s\n", indent, span.Name, span.Duration, span.ID)
+
+	keys := make([]string, 0, len(span.Tags))
+	for key := range span.Tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		fmt.Fprintf(buffer, "%s  | %s=%s\n", indent, key, quote(span.Tags[key]))
+	}
+
+	for _, annotation := range span.Annotations {
+		offset := annotation.Timestamp.Sub(span.Timestamp)
+		fmt.Fprintf(buffer, "%s  @ +%s %s\n", indent, offset, quote(annotation.Value))
+	}
+
+	for _, child := range children[span.ID] {
+		encodeNode(buffer, child, children, depth+1)
+	}
+}
+
+// quote escapes values spanning multiple lines so that the tree stays readable
+func quote(value string) string {
+	if strings.ContainsAny(value, "\n\r\t") {
+		return strconv.Quote(value)
+	}
+
+	return value
+}
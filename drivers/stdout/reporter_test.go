@@ -0,0 +1,144 @@
+package stdout
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openzipkin/zipkin-go/model"
+)
+
+func treeSpan(traceID uint64, id uint64, name string, root bool) model.SpanModel {
+	span := model.SpanModel{
+		SpanContext: model.SpanContext{TraceID: model.TraceID{Low: traceID}, ID: model.ID(id)},
+		Name:        name,
+		Timestamp:   time.Now(),
+		Tags:        map[string]string{},
+	}
+
+	if root {
+		span.Tags["uuid"] = "uuid"
+	}
+
+	return span
+}
+
+func TestTreeWrittenOnceRootFinishes(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	rep := NewReporter(buffer, Tree, false)
+	defer rep.Close()
+
+	rep.Send(treeSpan(1, 2, "child", false))
+	if buffer.Len() != 0 {
+		t.Fatal("expected the trace to be buffered until the root finishes")
+	}
+
+	rep.Send(treeSpan(1, 1, "root", true))
+	if !strings.Contains(buffer.String(), "child") || !strings.Contains(buffer.String(), "root") {
+		t.Errorf("expected the whole trace, got %q", buffer.String())
+	}
+}
+
+func TestLateSpanWrittenAfterRoot(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	rep := NewReporter(buffer, Tree, false)
+	defer rep.Close()
+
+	rep.Send(treeSpan(1, 1, "root", true))
+	buffer.Reset()
+
+	rep.Send(treeSpan(1, 2, "detached", false))
+	if !strings.Contains(buffer.String(), "detached") {
+		t.Errorf("expected the late span to be written right away, got %q", buffer.String())
+	}
+
+	if depth := rep.Stats().QueueDepth; depth != 0 {
+		t.Errorf("expected nothing buffered, got %d spans", depth)
+	}
+}
+
+func TestTraceWithoutRootWrittenOnTimeout(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	rep := NewReporter(buffer, Tree, false)
+	defer rep.Close()
+
+	rep.Send(treeSpan(1, 2, "orphan", false))
+
+	rep.expire(time.Now())
+	if buffer.Len() != 0 {
+		t.Fatal("expected the trace to be buffered within the timeout")
+	}
+
+	rep.expire(time.Now().Add(TreeTimeout + time.Second))
+	if !strings.Contains(buffer.String(), "orphan") {
+		t.Errorf("expected the trace to be written after the timeout, got %q", buffer.String())
+	}
+}
+
+func TestBufferedTracesCapped(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	rep := NewReporter(buffer, Tree, false)
+	defer rep.Close()
+
+	for i := uint64(1); i <= MaxBufferedTraces+1; i++ {
+		rep.Send(treeSpan(i, i, "span", false))
+	}
+
+	if depth := rep.Stats().QueueDepth; depth != MaxBufferedTraces {
+		t.Errorf("expected %d buffered spans, got %d", MaxBufferedTraces, depth)
+	}
+
+	if !strings.Contains(buffer.String(), "Trace 0000000000000001") {
+		t.Errorf("expected the oldest trace to be written, got %q", buffer.String())
+	}
+}
+
+// closingWriter records whether it has been closed
+type closingWriter struct {
+	bytes.Buffer
+	closed bool
+}
+
+func (writer *closingWriter) Close() error {
+	writer.closed = true
+	return nil
+}
+
+func TestCloseKeepsCallerWriterOpen(t *testing.T) {
+	writer := &closingWriter{}
+	if err := NewReporter(writer, JSON, false).Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if writer.closed {
+		t.Error("expected the writer of the caller to stay open")
+	}
+}
+
+func TestCloseKeepsStdoutOpen(t *testing.T) {
+	if err := NewReporter(os.Stdout, Tree, false).Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := os.Stdout.Stat(); err != nil {
+		t.Errorf("expected os.Stdout to stay open, got %v", err)
+	}
+}
+
+func TestCloseClosesRotatingFile(t *testing.T) {
+	file, err := NewRotatingFile(filepath.Join(t.TempDir(), "spans.log"), 1024, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := NewReporter(file, JSON, false).Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := file.Write([]byte("span\n")); err == nil {
+		t.Error("expected the rotating file to be closed")
+	}
+}
@@ -0,0 +1,123 @@
+package stdout
+
+import (
+	"fmt"
+	"os"
+	"sync"
+)
+
+// RotatingFile is an io.WriteCloser that rotates the file once it exceeds the maximum size,
+// keeping a limited number of backups (file.1 being the most recent one).
+// It should be initialized using NewRotatingFile method.
+type RotatingFile struct {
+	path       string
+	maxSize    int64
+	maxBackups int
+
+	mu     sync.Mutex
+	file   *os.File
+	size   int64
+	closed bool
+}
+
+// NewRotatingFile opens (or creates) the file at the given path for appending
+func NewRotatingFile(path string, maxSize int64, maxBackups int) (*RotatingFile, error) {
+	rotating := &RotatingFile{path: path, maxSize: maxSize, maxBackups: maxBackups}
+	if err := rotating.open(); err != nil {
+		return nil, err
+	}
+
+	return rotating, nil
+}
+
+// Write appends data to the file, rotating it first if the data would not fit
+func (rotating *RotatingFile) Write(p []byte) (int, error) {
+	rotating.mu.Lock()
+	defer rotating.mu.Unlock()
+
+	if rotating.closed {
+		return 0, os.ErrClosed
+	}
+
+	// The file could not be reopened after the last rotation
+	if rotating.file == nil {
+		if err := rotating.open(); err != nil {
+			return 0, err
+		}
+	}
+
+	if rotating.size > 0 && rotating.size+int64(len(p)) > rotating.maxSize {
+		if err := rotating.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := rotating.file.Write(p)
+	rotating.size += int64(n)
+
+	return n, err
+}
+
+// Close closes the current file
+func (rotating *RotatingFile) Close() error {
+	rotating.mu.Lock()
+	defer rotating.mu.Unlock()
+
+	rotating.closed = true
+	if rotating.file == nil {
+		return nil
+	}
+
+	err := rotating.file.Close()
+	rotating.file = nil
+
+	return err
+}
+
+func (rotating *RotatingFile) open() error {
+	file, err := os.OpenFile(rotating.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return err
+	}
+
+	rotating.file = file
+	rotating.size = info.Size()
+
+	return nil
+}
+
+// rotate moves the file to a backup and opens a new one. When the file cannot be moved,
+// the current one is reopened so that rotation is retried on the next write.
+func (rotating *RotatingFile) rotate() error {
+	_ = rotating.file.Close()
+	rotating.file = nil
+
+	if err := rotating.shift(); err != nil {
+		_ = rotating.open()
+		return err
+	}
+
+	return rotating.open()
+}
+
+func (rotating *RotatingFile) shift() error {
+	if rotating.maxBackups == 0 {
+		return os.Remove(rotating.path)
+	}
+
+	for i := rotating.maxBackups - 1; i > 0; i-- {
+		_ = os.Rename(rotating.backup(i), rotating.backup(i+1))
+	}
+
+	return os.Rename(rotating.path, rotating.backup(1))
+}
+
+func (rotating *RotatingFile) backup(n int) string {
+	return fmt.Sprintf("%s.%d", rotating.path, n)
+}
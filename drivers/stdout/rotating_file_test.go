@@ -0,0 +1,115 @@
+package stdout
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(data)
+}
+
+func TestRotatingFileKeepsBackups(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "spans.log")
+
+	file, err := NewRotatingFile(path, 4, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	for _, line := range []string{"one\n", "two\n", "six\n"} {
+		if _, err := file.Write([]byte(line)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	expected := map[string]string{path: "six\n", path + ".1": "two\n", path + ".2": "one\n"}
+	for name, content := range expected {
+		if got := readFile(t, name); got != content {
+			t.Errorf("%s: expected %q, got %q", name, content, got)
+		}
+	}
+}
+
+func TestRotatingFileWithoutBackups(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "spans.log")
+
+	file, err := NewRotatingFile(path, 4, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	_, _ = file.Write([]byte("one\n"))
+	_, _ = file.Write([]byte("two\n"))
+
+	if got := readFile(t, path); got != "two\n" {
+		t.Errorf("expected the file to be truncated, got %q", got)
+	}
+	if _, err := os.Stat(path + ".1"); !os.IsNotExist(err) {
+		t.Errorf("expected no backup, got %v", err)
+	}
+}
+
+func TestRotatingFileAppendsToExisting(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "spans.log")
+	if err := ioutil.WriteFile(path, []byte("old\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	file, err := NewRotatingFile(path, 1024, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	_, _ = file.Write([]byte("new\n"))
+
+	if got := readFile(t, path); got != "old\nnew\n" {
+		t.Errorf("expected appended content, got %q", got)
+	}
+}
+
+func TestRotatingFileRecoversFromFailedRotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "spans.log")
+
+	file, err := NewRotatingFile(path, 4, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	// A non-empty directory in place of the backup makes the rename fail
+	if err := os.MkdirAll(filepath.Join(path+".1", "blocker"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	_, _ = file.Write([]byte("one\n"))
+	if _, err := file.Write([]byte("two\n")); err == nil {
+		t.Fatal("expected the failed rotation to be returned")
+	}
+
+	if err := os.RemoveAll(path + ".1"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := file.Write([]byte("six\n")); err != nil {
+		t.Fatalf("expected writes to continue after a failed rotation, got %v", err)
+	}
+	if got := readFile(t, path); got != "six\n" {
+		t.Errorf("expected the rotation to be retried, got %q", got)
+	}
+	if got := readFile(t, path+".1"); got != "one\n" {
+		t.Errorf("expected the backup, got %q", got)
+	}
+}
@@ -0,0 +1,43 @@
+package stdout
+
+import (
+	"io"
+	"os"
+
+	"github.com/Vinelab/tracing-go/drivers/zipkin"
+)
+
+// TracerOptions is a configuration container to setup the Tracer.
+type TracerOptions struct {
+	// ServiceName is the name of application you're tracing
+	// Required
+	ServiceName string
+	// Writer receives finished spans, see NewRotatingFile for writing into a file
+	// Defaults to os.Stdout
+	Writer io.Writer
+	// Format tells how spans are written
+	// Defaults to JSON
+	Format Format
+	// Pretty indents JSON output
+	// Defaults to false
+	Pretty bool
+	// UsesTraceID128Bit tells whether to use 128 bit trace IDs (32 characters in length as opposed to 16)
+	// Defaults to false
+	UsesTraceID128Bit bool
+}
+
+// NewTracer returns a new tracer that writes finished spans to the writer instead of sending them
+// to a collector. It is a Zipkin tracer under the hood, so it supports all propagation formats
+// using B3 headers and can be used as a drop-in replacement during local development.
+func NewTracer(opt TracerOptions) (*zipkin.Tracer, error) {
+	writer := opt.Writer
+	if writer == nil {
+		writer = os.Stdout
+	}
+
+	return zipkin.NewTracer(zipkin.TracerOptions{
+		ServiceName:       opt.ServiceName,
+		UsesTraceID128Bit: opt.UsesTraceID128Bit,
+		Reporter:          NewReporter(writer, opt.Format, opt.Pretty),
+	})
+}
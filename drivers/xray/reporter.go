@@ -0,0 +1,113 @@
+package xray
+
+import (
+	"encoding/json"
+	"errors"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/Vinelab/tracing-go"
+	"github.com/openzipkin/zipkin-go/model"
+)
+
+const (
+	// MaxSegmentSize controls the maximum size of UDP packet X-Ray daemon accepts
+	MaxSegmentSize = 64 * 1024
+)
+
+var (
+	// ErrSegmentTooLarge is recorded when the segment does not fit into UDP packet even without metadata
+	ErrSegmentTooLarge = errors.New("segment document exceeds maximum UDP packet size")
+
+	// header precedes every segment document sent to X-Ray daemon
+	header = []byte("{\"format\": \"json\", \"version\": 1}\n")
+)
+
+// Reporter sends finished spans to X-Ray daemon over UDP. It should be initialized using NewReporter method.
+type Reporter struct {
+	conn        net.Conn
+	indexedTags map[string]bool
+
+	mu          sync.Mutex
+	reported    uint64
+	dropped     uint64
+	lastError   string
+	lastErrorAt time.Time
+	latency     time.Duration
+}
+
+// NewReporter returns a new Reporter sending segments to the daemon at given UDP address
+func NewReporter(address string, indexedTags []string) (*Reporter, error) {
+	conn, err := net.Dial("udp", address)
+	if err != nil {
+		return nil, err
+	}
+
+	indexed := make(map[string]bool, len(indexedTags))
+	for _, tag := range indexedTags {
+		indexed[tag] = true
+	}
+
+	return &Reporter{conn: conn, indexedTags: indexed}, nil
+}
+
+// Send converts the span into segment document and sends it to the daemon
+func (rep *Reporter) Send(span model.SpanModel) {
+	doc := newSegment(span, rep.indexedTags)
+
+	packet, err := encode(doc)
+	if err == nil && len(packet) > MaxSegmentSize {
+		// Metadata holds arbitrary large tags (i.e. request and response bodies), so try again without it
+		doc.Metadata = nil
+		if packet, err = encode(doc); err == nil && len(packet) > MaxSegmentSize {
+			err = ErrSegmentTooLarge
+		}
+	}
+
+	start := time.Now()
+	if err == nil {
+		_, err = rep.conn.Write(packet)
+	}
+
+	rep.mu.Lock()
+	defer rep.mu.Unlock()
+
+	if err != nil {
+		rep.dropped++
+		rep.lastError = err.Error()
+		rep.lastErrorAt = time.Now()
+		return
+	}
+
+	rep.reported++
+	rep.latency = time.Since(start)
+}
+
+// Close closes the UDP connection
+func (rep *Reporter) Close() error {
+	return rep.conn.Close()
+}
+
+// Stats retrieves reporter related self-telemetry
+func (rep *Reporter) Stats() tracing.Stats {
+	rep.mu.Lock()
+	defer rep.mu.Unlock()
+
+	return tracing.Stats{
+		SpansReported:     rep.reported,
+		SpansDropped:      rep.dropped,
+		LastExportError:   rep.lastError,
+		LastExportErrorAt: rep.lastErrorAt,
+		ExportLatency:     rep.latency,
+	}
+}
+
+func encode(doc segment) ([]byte, error) {
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(append([]byte{}, header...), data...), nil
+}
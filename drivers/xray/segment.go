@@ -0,0 +1,150 @@
+package xray
+
+import (
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/Vinelab/tracing-go/drivers/zipkin/propagation"
+	"github.com/openzipkin/zipkin-go/model"
+)
+
+const (
+	// MaxNameLen controls the maximum size of segment name X-Ray accepts
+	MaxNameLen = 200
+)
+
+var (
+	invalidNameChars          = regexp.MustCompile(`[^\pL\pN\s_.:/%&#=+\\\-@]`)
+	invalidAnnotationKeyChars = regexp.MustCompile(`[^a-zA-Z0-9_]`)
+)
+
+// segment is the document X-Ray daemon accepts, both for segments and independently sent subsegments.
+// See https://docs.aws.amazon.com/xray/latest/devguide/xray-api-segmentdocuments.html
+type segment struct {
+	Name        string                            `json:"name"`
+	ID          string                            `json:"id"`
+	TraceID     string                            `json:"trace_id"`
+	ParentID    string                            `json:"parent_id,omitempty"`
+	Type        string                            `json:"type,omitempty"`
+	StartTime   float64                           `json:"start_time"`
+	EndTime     float64                           `json:"end_time"`
+	Error       bool                              `json:"error,omitempty"`
+	Fault       bool                              `json:"fault,omitempty"`
+	HTTP        *segmentHTTP                      `json:"http,omitempty"`
+	Annotations map[string]string                 `json:"annotations,omitempty"`
+	Metadata    map[string]map[string]interface{} `json:"metadata,omitempty"`
+}
+
+type segmentHTTP struct {
+	Request  *segmentRequest  `json:"request,omitempty"`
+	Response *segmentResponse `json:"response,omitempty"`
+}
+
+type segmentRequest struct {
+	Method   string `json:"method,omitempty"`
+	URL      string `json:"url,omitempty"`
+	ClientIP string `json:"client_ip,omitempty"`
+}
+
+type segmentResponse struct {
+	Status int `json:"status,omitempty"`
+}
+
+type segmentAnnotation struct {
+	Timestamp float64 `json:"timestamp"`
+	Value     string  `json:"value"`
+}
+
+// newSegment converts the span into segment document. Root span of the service (tagged with uuid
+// by the tracer) becomes a segment named by the service, the rest of spans become subsegments.
+func newSegment(span model.SpanModel, indexedTags map[string]bool) segment {
+	doc := segment{
+		Name:      sanitizeName(span.Name),
+		ID:        span.ID.String(),
+		TraceID:   propagation.XRayTraceID(span.TraceID),
+		StartTime: epochSeconds(span.Timestamp),
+		EndTime:   epochSeconds(span.Timestamp.Add(span.Duration)),
+	}
+
+	if span.ParentID != nil {
+		doc.ParentID = span.ParentID.String()
+	}
+
+	if _, isRoot := span.Tags["uuid"]; !isRoot && doc.ParentID != "" {
+		doc.Type = "subsegment"
+	} else if span.LocalEndpoint != nil && span.LocalEndpoint.ServiceName != "" {
+		doc.Name = sanitizeName(span.LocalEndpoint.ServiceName)
+	}
+
+	metadata := map[string]interface{}{"name": span.Name}
+
+	tags := make(map[string]string, len(span.Tags))
+	for key, value := range span.Tags {
+		if indexedTags[key] {
+			if doc.Annotations == nil {
+				doc.Annotations = make(map[string]string)
+			}
+			doc.Annotations[invalidAnnotationKeyChars.ReplaceAllString(key, "_")] = value
+			continue
+		}
+
+		tags[key] = value
+	}
+	if len(tags) > 0 {
+		metadata["tags"] = tags
+	}
+
+	if len(span.Annotations) > 0 {
+		annotations := make([]segmentAnnotation, 0, len(span.Annotations))
+		for _, annotation := range span.Annotations {
+			annotations = append(annotations, segmentAnnotation{
+				Timestamp: epochSeconds(annotation.Timestamp),
+				Value:     annotation.Value,
+			})
+		}
+		metadata["annotations"] = annotations
+	}
+
+	doc.Metadata = map[string]map[string]interface{}{"default": metadata}
+
+	if _, ok := span.Tags["error"]; ok {
+		doc.Fault = true
+	}
+
+	// Tags recorded by TraceRequests middleware
+	if method, ok := span.Tags["request_method"]; ok {
+		doc.HTTP = &segmentHTTP{Request: &segmentRequest{
+			Method:   method,
+			URL:      span.Tags["request_uri"],
+			ClientIP: span.Tags["request_ip"],
+		}}
+
+		if status, err := strconv.Atoi(span.Tags["response_status"]); err == nil {
+			doc.HTTP.Response = &segmentResponse{Status: status}
+			doc.Error = doc.Error || (status >= 400 && status < 500)
+			doc.Fault = doc.Fault || status >= 500
+		}
+	}
+
+	return doc
+}
+
+func sanitizeName(name string) string {
+	name = invalidNameChars.ReplaceAllString(name, "_")
+
+	runes := []rune(name)
+	if len(runes) > MaxNameLen {
+		name = string(runes[:MaxNameLen])
+	}
+
+	if name == "" {
+		return "unknown"
+	}
+
+	return name
+}
+
+func epochSeconds(t time.Time) float64 {
+	return float64(t.UnixNano()/int64(time.Microsecond)) / 1e6
+}
@@ -0,0 +1,80 @@
+package xray
+
+import (
+	"errors"
+	"os"
+	"strings"
+
+	"github.com/Vinelab/tracing-go/drivers/zipkin"
+	"github.com/openzipkin/zipkin-go/idgenerator"
+)
+
+const (
+	// DefaultDaemonAddress is the address X-Ray daemon listens on by default
+	DefaultDaemonAddress = "127.0.0.1:2000"
+)
+
+var (
+	// ErrInvalidDaemonAddress is returned when the daemon address has no UDP address to send segments to
+	ErrInvalidDaemonAddress = errors.New("invalid X-Ray daemon address")
+)
+
+// TracerOptions is a configuration container to setup the Tracer.
+type TracerOptions struct {
+	// ServiceName is the name of application you're tracing, it is used as the name of segments
+	// Required
+	ServiceName string
+	// DaemonAddress is the UDP address of X-Ray daemon, either host:port or "tcp:host:port udp:host:port"
+	// as in AWS_XRAY_DAEMON_ADDRESS, in which case the UDP one is used
+	// Defaults to AWS_XRAY_DAEMON_ADDRESS environment variable or DefaultDaemonAddress
+	DaemonAddress string
+	// IndexedTags lists tags that are recorded as X-Ray annotations, so that traces can be searched by them.
+	// The rest of tags are recorded as metadata
+	// Defaults to none
+	IndexedTags []string
+}
+
+// NewTracer returns a new tracer that sends segment documents to X-Ray daemon. It is a Zipkin tracer
+// under the hood generating X-Ray compatible (time-based 128 bit) trace IDs, so use formats.XRay
+// to propagate the trace context via X-Amzn-Trace-Id header.
+func NewTracer(opt TracerOptions) (*zipkin.Tracer, error) {
+	address := opt.DaemonAddress
+	if address == "" {
+		address = os.Getenv("AWS_XRAY_DAEMON_ADDRESS")
+	}
+	if address == "" {
+		address = DefaultDaemonAddress
+	}
+
+	address, err := udpAddress(address)
+	if err != nil {
+		return nil, err
+	}
+
+	rep, err := NewReporter(address, opt.IndexedTags)
+	if err != nil {
+		return nil, err
+	}
+
+	return zipkin.NewTracer(zipkin.TracerOptions{
+		ServiceName: opt.ServiceName,
+		Reporter:    rep,
+		IDGenerator: idgenerator.NewRandomTimestamped(),
+	})
+}
+
+// udpAddress resolves the UDP address of the daemon from either host:port or "tcp:host:port udp:host:port" form
+func udpAddress(address string) (string, error) {
+	fields := strings.Fields(address)
+	if len(fields) == 1 && !strings.HasPrefix(fields[0], "tcp:") && !strings.HasPrefix(fields[0], "udp:") {
+		return fields[0], nil
+	}
+
+	for _, field := range fields {
+		if strings.HasPrefix(field, "udp:") {
+			return strings.TrimPrefix(field, "udp:"), nil
+		}
+	}
+
+	return "", ErrInvalidDaemonAddress
+}
@@ -0,0 +1,74 @@
+package xray
+
+import (
+	"bytes"
+	"encoding/json"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestUDPAddress(t *testing.T) {
+	cases := map[string]string{
+		"127.0.0.1:2000":                          "127.0.0.1:2000",
+		"udp:10.0.0.1:2000":                       "10.0.0.1:2000",
+		"tcp:10.0.0.1:2001 udp:10.0.0.2:2002":     "10.0.0.2:2002",
+		"  udp:10.0.0.2:2002   tcp:10.0.0.1:2001": "10.0.0.2:2002",
+	}
+
+	for address, expected := range cases {
+		actual, err := udpAddress(address)
+		if err != nil || actual != expected {
+			t.Errorf("%q: expected %q, got %q (%v)", address, expected, actual, err)
+		}
+	}
+
+	if _, err := udpAddress("tcp:10.0.0.1:2001"); err != ErrInvalidDaemonAddress {
+		t.Errorf("expected ErrInvalidDaemonAddress, got %v", err)
+	}
+}
+
+func TestSegmentsSentToDaemon(t *testing.T) {
+	daemon, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer daemon.Close()
+
+	t.Setenv("AWS_XRAY_DAEMON_ADDRESS", "tcp:127.0.0.1:1 udp:"+daemon.LocalAddr().String())
+
+	tracer, err := NewTracer(TracerOptions{ServiceName: "xray-test", IndexedTags: []string{"user_id"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tracer.Close()
+
+	span := tracer.StartSpan("Request", tracer.EmptySpanContext())
+	span.Tag("user_id", "42")
+	span.Finish()
+
+	_ = daemon.SetReadDeadline(time.Now().Add(5 * time.Second))
+	packet := make([]byte, MaxSegmentSize)
+	n, _, err := daemon.ReadFrom(packet)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parts := bytes.SplitN(packet[:n], []byte("\n"), 2)
+	if len(parts) != 2 || string(parts[0]) != `{"format": "json", "version": 1}` {
+		t.Fatalf("expected header followed by segment, got %q", packet[:n])
+	}
+
+	var doc segment
+	if err := json.Unmarshal(parts[1], &doc); err != nil {
+		t.Fatal(err)
+	}
+
+	if doc.Name != "xray-test" || doc.Annotations["user_id"] != "42" {
+		t.Errorf("unexpected segment %+v", doc)
+	}
+
+	if stats := tracer.Stats(); stats.SpansReported != 1 {
+		t.Errorf("expected 1 reported span, got %+v", stats)
+	}
+}
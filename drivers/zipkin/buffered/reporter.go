@@ -0,0 +1,452 @@
+package buffered
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Vinelab/tracing-go"
+	"github.com/openzipkin/zipkin-go/model"
+	"github.com/openzipkin/zipkin-go/reporter"
+)
+
+const (
+	// DefaultQueueSize controls the default maximum number of spans buffered in memory
+	DefaultQueueSize = 1000
+	// DefaultBatchSize controls the default maximum number of spans sent in a single request
+	DefaultBatchSize = 100
+	// DefaultBatchInterval controls how often buffered spans are sent by default
+	DefaultBatchInterval = time.Second
+	// DefaultRequestTimeout sets the default maximum timeout for http request to send spans
+	DefaultRequestTimeout = time.Second * 5
+	// DefaultMinBackoff sets the default delay before the first retry after collector failure
+	DefaultMinBackoff = time.Second
+	// DefaultMaxBackoff sets the default maximum delay between retries
+	DefaultMaxBackoff = time.Minute
+	// DefaultMaxSpoolSize controls the default maximum size of the spool directory in bytes
+	DefaultMaxSpoolSize = 64 * 1024 * 1024
+)
+
+// Reporter sends spans to Zipkin collector in batches. When collector is down, it backs off
+// exponentially and spools batches to disk (if configured), which are replayed once collector
+// is back. Spans that overflow the in-memory queue are spooled as well. Batches rejected by
+// collector (4xx other than 429) are dropped, as retrying them would never succeed.
+// It should be initialized using NewReporter method.
+type Reporter struct {
+	url           string
+	client        *http.Client
+	serializer    reporter.SpanSerializer
+	queue         chan *model.SpanModel
+	batchSize     int
+	batchInterval time.Duration
+	minBackoff    time.Duration
+	maxBackoff    time.Duration
+	spool         *spool
+
+	backoff time.Duration
+	retryAt time.Time
+
+	// sendMu guards closed, so that no span is enqueued once the queue is drained on Close
+	sendMu sync.RWMutex
+	closed bool
+
+	overflowMu sync.Mutex
+	overflow   []*model.SpanModel
+
+	dropped  uint64
+	reported uint64
+
+	mu                sync.Mutex
+	lastError         string
+	lastErrorAt       time.Time
+	lastExportLatency time.Duration
+
+	quit      chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// Options is a configuration container to setup the Reporter.
+type Options struct {
+	// QueueSize controls the maximum number of spans buffered in memory.
+	// Spans that do not fit are spooled in batches, or dropped when there is no spool
+	// Defaults to DefaultQueueSize
+	QueueSize int
+	// BatchSize controls the maximum number of spans sent in a single request
+	// Defaults to DefaultBatchSize
+	BatchSize int
+	// BatchInterval controls how often buffered spans are sent
+	// Defaults to DefaultBatchInterval
+	BatchInterval time.Duration
+	// RequestTimeout sets maximum timeout for http request to send spans
+	// Defaults to DefaultRequestTimeout
+	RequestTimeout time.Duration
+	// MinBackoff sets the delay before the first retry after collector failure
+	// Defaults to DefaultMinBackoff
+	MinBackoff time.Duration
+	// MaxBackoff sets the maximum delay between retries, the delay doubles after every failure
+	// Defaults to DefaultMaxBackoff
+	MaxBackoff time.Duration
+	// SpoolDir is the directory where batches are spooled while collector is down
+	// Defaults to none, meaning batches are dropped
+	SpoolDir string
+	// MaxSpoolSize controls the maximum size of the spool directory in bytes
+	// Defaults to DefaultMaxSpoolSize
+	MaxSpoolSize int64
+	// Client is the http client used to send spans
+	// Defaults to http.Client with RequestTimeout
+	Client *http.Client
+	// Serializer encodes batches of spans, allowing to send them to collectors of other vendors
+	// Defaults to Zipkin v2 JSON
+	Serializer reporter.SpanSerializer
+}
+
+// NewReporter returns a new Reporter that sends spans to the given collector url
+// (i.e. http://localhost:9411/api/v2/spans)
+func NewReporter(url string, opt Options) (*Reporter, error) {
+	if opt.QueueSize == 0 {
+		opt.QueueSize = DefaultQueueSize
+	}
+	if opt.BatchSize == 0 {
+		opt.BatchSize = DefaultBatchSize
+	}
+	if opt.BatchInterval == 0 {
+		opt.BatchInterval = DefaultBatchInterval
+	}
+	if opt.RequestTimeout == 0 {
+		opt.RequestTimeout = DefaultRequestTimeout
+	}
+	if opt.MinBackoff == 0 {
+		opt.MinBackoff = DefaultMinBackoff
+	}
+	if opt.MaxBackoff == 0 {
+		opt.MaxBackoff = DefaultMaxBackoff
+	}
+	if opt.MaxSpoolSize == 0 {
+		opt.MaxSpoolSize = DefaultMaxSpoolSize
+	}
+	if opt.Client == nil {
+		opt.Client = &http.Client{Timeout: opt.RequestTimeout}
+	}
+	if opt.Serializer == nil {
+		opt.Serializer = reporter.JSONSerializer{}
+	}
+
+	rep := &Reporter{
+		url:           url,
+		client:        opt.Client,
+		serializer:    opt.Serializer,
+		queue:         make(chan *model.SpanModel, opt.QueueSize),
+		batchSize:     opt.BatchSize,
+		batchInterval: opt.BatchInterval,
+		minBackoff:    opt.MinBackoff,
+		maxBackoff:    opt.MaxBackoff,
+		quit:          make(chan struct{}),
+		done:          make(chan struct{}),
+	}
+
+	if opt.SpoolDir != "" {
+		s, err := newSpool(opt.SpoolDir, extension(opt.Serializer.ContentType()), opt.MaxSpoolSize)
+		if err != nil {
+			return nil, err
+		}
+		rep.spool = s
+	}
+
+	go rep.loop()
+
+	return rep, nil
+}
+
+// Send enqueues the span without blocking. When the queue is full, the span is spooled along with
+// other overflowing spans, or dropped when there is no spool. Spans sent after Close are dropped
+func (rep *Reporter) Send(span model.SpanModel) {
+	rep.sendMu.RLock()
+	defer rep.sendMu.RUnlock()
+
+	if rep.closed {
+		atomic.AddUint64(&rep.dropped, 1)
+		return
+	}
+
+	select {
+	case rep.queue <- &span:
+	default:
+		rep.spill(&span)
+	}
+}
+
+// Close sends buffered spans, spooling those that can't be delivered, and stops the reporter
+func (rep *Reporter) Close() error {
+	rep.closeOnce.Do(func() {
+		rep.sendMu.Lock()
+		rep.closed = true
+		rep.sendMu.Unlock()
+
+		close(rep.quit)
+	})
+	<-rep.done
+
+	return nil
+}
+
+// Dropped returns the number of spans that were neither delivered nor spooled
+func (rep *Reporter) Dropped() uint64 {
+	return atomic.LoadUint64(&rep.dropped)
+}
+
+// Stats retrieves reporter related self-telemetry
+func (rep *Reporter) Stats() tracing.Stats {
+	rep.mu.Lock()
+	defer rep.mu.Unlock()
+
+	return tracing.Stats{
+		SpansReported:     atomic.LoadUint64(&rep.reported),
+		SpansDropped:      atomic.LoadUint64(&rep.dropped),
+		QueueDepth:        rep.QueueDepth() + rep.Spooled(),
+		LastExportError:   rep.lastError,
+		LastExportErrorAt: rep.lastErrorAt,
+		ExportLatency:     rep.lastExportLatency,
+	}
+}
+
+// QueueDepth returns the number of spans waiting in memory
+func (rep *Reporter) QueueDepth() int {
+	rep.overflowMu.Lock()
+	defer rep.overflowMu.Unlock()
+
+	return len(rep.queue) + len(rep.overflow)
+}
+
+// Spooled returns the number of spans waiting on disk
+func (rep *Reporter) Spooled() int {
+	if rep.spool == nil {
+		return 0
+	}
+
+	return rep.spool.spans()
+}
+
+func (rep *Reporter) loop() {
+	defer close(rep.done)
+
+	ticker := time.NewTicker(rep.batchInterval)
+	defer ticker.Stop()
+
+	batch := make([]*model.SpanModel, 0, rep.batchSize)
+	for {
+		select {
+		case span := <-rep.queue:
+			batch = append(batch, span)
+			if len(batch) >= rep.batchSize {
+				batch = rep.flush(batch)
+			}
+		case <-ticker.C:
+			batch = rep.flush(batch)
+			rep.spoolOverflow(0)
+			rep.replay()
+		case <-rep.quit:
+			for len(rep.queue) > 0 {
+				batch = append(batch, <-rep.queue)
+				if len(batch) >= rep.batchSize {
+					batch = rep.flush(batch)
+				}
+			}
+			rep.flush(batch)
+			rep.spoolOverflow(0)
+			return
+		}
+	}
+}
+
+// spill collects the span that overflowed the queue, spooling overflowing spans once they fill a batch
+func (rep *Reporter) spill(span *model.SpanModel) {
+	if rep.spool == nil {
+		atomic.AddUint64(&rep.dropped, 1)
+		return
+	}
+
+	rep.overflowMu.Lock()
+	rep.overflow = append(rep.overflow, span)
+	rep.overflowMu.Unlock()
+
+	rep.spoolOverflow(rep.batchSize)
+}
+
+// spoolOverflow spools overflowing spans once there are at least min of them
+func (rep *Reporter) spoolOverflow(min int) {
+	rep.overflowMu.Lock()
+	if len(rep.overflow) == 0 || len(rep.overflow) < min {
+		rep.overflowMu.Unlock()
+		return
+	}
+
+	batch := rep.overflow
+	rep.overflow = nil
+	rep.overflowMu.Unlock()
+
+	rep.store(batch)
+}
+
+// flush sends the batch unless the reporter backs off, then returns an empty batch
+func (rep *Reporter) flush(batch []*model.SpanModel) []*model.SpanModel {
+	if len(batch) == 0 {
+		return batch
+	}
+
+	if time.Now().Before(rep.retryAt) {
+		rep.store(batch)
+		return batch[:0]
+	}
+
+	body, err := rep.serializer.Serialize(batch)
+	if err != nil {
+		atomic.AddUint64(&rep.dropped, uint64(len(batch)))
+		return batch[:0]
+	}
+
+	err = rep.post(body, len(batch))
+	switch {
+	case err == nil:
+		rep.recover()
+	case retryable(err):
+		rep.fail()
+		rep.storeSerialized(body, len(batch))
+	default:
+		atomic.AddUint64(&rep.dropped, uint64(len(batch)))
+	}
+
+	return batch[:0]
+}
+
+// replay sends spooled batches, oldest first, until the spool is empty or collector fails
+func (rep *Reporter) replay() {
+	if rep.spool == nil {
+		return
+	}
+
+	for !time.Now().Before(rep.retryAt) {
+		name, body, err := rep.spool.oldest()
+		if err != nil && name != "" {
+			// The batch is unreadable, replaying it again would never succeed
+			atomic.AddUint64(&rep.dropped, uint64(spanCount(name)))
+			rep.spool.remove(name)
+			continue
+		}
+		if err != nil || body == nil {
+			return
+		}
+
+		err = rep.post(body, spanCount(name))
+		switch {
+		case err == nil:
+			rep.recover()
+		case retryable(err):
+			rep.fail()
+			return
+		default:
+			atomic.AddUint64(&rep.dropped, uint64(spanCount(name)))
+		}
+
+		rep.spool.remove(name)
+	}
+}
+
+// post sends serialized batch of count spans and records the outcome
+func (rep *Reporter) post(body []byte, count int) error {
+	start := time.Now()
+	err := rep.do(body)
+
+	rep.mu.Lock()
+	rep.lastExportLatency = time.Since(start)
+	if err != nil {
+		rep.lastError = err.Error()
+		rep.lastErrorAt = time.Now()
+	}
+	rep.mu.Unlock()
+
+	if err == nil {
+		atomic.AddUint64(&rep.reported, uint64(count))
+	}
+
+	return err
+}
+
+func (rep *Reporter) do(body []byte) error {
+	req, err := http.NewRequest(http.MethodPost, rep.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", rep.serializer.ContentType())
+
+	resp, err := rep.client.Do(req)
+	if err != nil {
+		return err
+	}
+	_ = resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return &StatusError{StatusCode: resp.StatusCode}
+	}
+
+	return nil
+}
+
+// StatusError is returned when collector responds with a non-2xx status code
+type StatusError struct {
+	StatusCode int
+}
+
+func (err *StatusError) Error() string {
+	return fmt.Sprintf("collector responded with status %d", err.StatusCode)
+}
+
+// retryable tells whether the batch may be delivered later. Transport errors, server errors
+// and throttling are temporary, while the rest of client errors mean the batch is rejected for good
+func retryable(err error) bool {
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) {
+		return true
+	}
+
+	return statusErr.StatusCode >= 500 || statusErr.StatusCode == http.StatusTooManyRequests
+}
+
+// fail doubles the backoff delay, starting from the minimum one
+func (rep *Reporter) fail() {
+	if rep.backoff == 0 {
+		rep.backoff = rep.minBackoff
+	} else {
+		rep.backoff *= 2
+		if rep.backoff > rep.maxBackoff {
+			rep.backoff = rep.maxBackoff
+		}
+	}
+
+	rep.retryAt = time.Now().Add(rep.backoff)
+}
+
+func (rep *Reporter) recover() {
+	rep.backoff = 0
+	rep.retryAt = time.Time{}
+}
+
+func (rep *Reporter) store(batch []*model.SpanModel) {
+	body, err := rep.serializer.Serialize(batch)
+	if err != nil {
+		atomic.AddUint64(&rep.dropped, uint64(len(batch)))
+		return
+	}
+
+	rep.storeSerialized(body, len(batch))
+}
+
+func (rep *Reporter) storeSerialized(body []byte, count int) {
+	if rep.spool == nil || rep.spool.write(body, count) != nil {
+		atomic.AddUint64(&rep.dropped, uint64(count))
+	}
+}
@@ -0,0 +1,306 @@
+package buffered
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/openzipkin/zipkin-go/model"
+)
+
+// collector responds with statuses in order, repeating the last one
+type collector struct {
+	statuses []int
+	requests int64
+}
+
+func (c *collector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	n := int(atomic.AddInt64(&c.requests, 1)) - 1
+	if n >= len(c.statuses) {
+		n = len(c.statuses) - 1
+	}
+
+	w.WriteHeader(c.statuses[n])
+}
+
+func (c *collector) received() int {
+	return int(atomic.LoadInt64(&c.requests))
+}
+
+func newTestReporter(t *testing.T, c *collector, opt Options) *Reporter {
+	t.Helper()
+
+	srv := httptest.NewServer(c)
+	t.Cleanup(srv.Close)
+
+	rep, err := NewReporter(srv.URL, opt)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return rep
+}
+
+func span(id uint64) model.SpanModel {
+	return model.SpanModel{
+		SpanContext: model.SpanContext{TraceID: model.TraceID{Low: id}, ID: model.ID(id)},
+		Name:        "test",
+	}
+}
+
+func eventually(t *testing.T, timeout time.Duration, condition func() bool) {
+	t.Helper()
+
+	deadline := time.Now().Add(timeout)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition was not met in time")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestBatchesDelivered(t *testing.T) {
+	c := &collector{statuses: []int{http.StatusAccepted}}
+	rep := newTestReporter(t, c, Options{BatchSize: 2})
+
+	for i := uint64(1); i <= 4; i++ {
+		rep.Send(span(i))
+	}
+	_ = rep.Close()
+
+	stats := rep.Stats()
+	if stats.SpansReported != 4 || stats.SpansDropped != 0 {
+		t.Errorf("expected 4 reported spans, got %+v", stats)
+	}
+
+	if c.received() != 2 {
+		t.Errorf("expected 2 batches, got %d", c.received())
+	}
+}
+
+func TestClientErrorDropsBatch(t *testing.T) {
+	c := &collector{statuses: []int{http.StatusBadRequest}}
+	rep := newTestReporter(t, c, Options{SpoolDir: t.TempDir()})
+
+	rep.Send(span(1))
+	rep.Send(span(2))
+	_ = rep.Close()
+
+	if rep.Dropped() != 2 || rep.Spooled() != 0 {
+		t.Errorf("expected 2 dropped and none spooled, got %d dropped and %d spooled", rep.Dropped(), rep.Spooled())
+	}
+
+	if !rep.retryAt.IsZero() {
+		t.Error("rejected batch must not trigger backoff")
+	}
+}
+
+func TestRetryableErrorsSpoolAndReplay(t *testing.T) {
+	for _, status := range []int{http.StatusServiceUnavailable, http.StatusTooManyRequests} {
+		c := &collector{statuses: []int{status, http.StatusAccepted}}
+		rep := newTestReporter(t, c, Options{
+			SpoolDir:      t.TempDir(),
+			BatchInterval: 10 * time.Millisecond,
+			MinBackoff:    10 * time.Millisecond,
+		})
+
+		rep.Send(span(1))
+
+		eventually(t, time.Second, func() bool {
+			return rep.Stats().SpansReported == 1
+		})
+		_ = rep.Close()
+
+		if rep.Dropped() != 0 || rep.Spooled() != 0 {
+			t.Errorf("status %d: expected the batch to be replayed, got %d dropped and %d spooled",
+				status, rep.Dropped(), rep.Spooled())
+		}
+	}
+}
+
+func TestTransportErrorSpoolsBatch(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	srv.Close()
+
+	rep, err := NewReporter(srv.URL, Options{SpoolDir: t.TempDir()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rep.Send(span(1))
+	_ = rep.Close()
+
+	if rep.Dropped() != 0 || rep.Spooled() != 1 {
+		t.Errorf("expected the span to be spooled, got %d dropped and %d spooled", rep.Dropped(), rep.Spooled())
+	}
+}
+
+func TestReplayDrainsSpool(t *testing.T) {
+	dir := t.TempDir()
+
+	s, err := newSpool(dir, ".json", DefaultMaxSpoolSize)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		if err := s.write([]byte("[]"), 1); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	c := &collector{statuses: []int{http.StatusAccepted}}
+	rep := newTestReporter(t, c, Options{SpoolDir: dir, BatchInterval: 500 * time.Millisecond})
+	defer rep.Close()
+
+	// Every batch is replayed on the first tick rather than one batch per tick
+	eventually(t, 900*time.Millisecond, func() bool {
+		return c.received() == 3
+	})
+
+	if rep.Spooled() != 0 {
+		t.Errorf("expected empty spool, got %d spans", rep.Spooled())
+	}
+}
+
+func TestRejectedSpooledBatchRemoved(t *testing.T) {
+	dir := t.TempDir()
+
+	s, err := newSpool(dir, ".json", DefaultMaxSpoolSize)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.write([]byte("not a batch"), 2); err != nil {
+		t.Fatal(err)
+	}
+
+	c := &collector{statuses: []int{http.StatusBadRequest}}
+	rep := newTestReporter(t, c, Options{SpoolDir: dir, BatchInterval: 10 * time.Millisecond})
+
+	eventually(t, time.Second, func() bool {
+		return rep.Spooled() == 0
+	})
+	_ = rep.Close()
+
+	if rep.Dropped() != 2 || c.received() != 1 {
+		t.Errorf("expected the batch to be dropped once, got %d dropped after %d requests", rep.Dropped(), c.received())
+	}
+}
+
+func TestFullQueueSpoolsOverflow(t *testing.T) {
+	c := &collector{statuses: []int{http.StatusAccepted}}
+	rep := newTestReporter(t, c, Options{QueueSize: 1, BatchSize: 100, SpoolDir: t.TempDir()})
+
+	for i := uint64(1); i <= 1000; i++ {
+		rep.Send(span(i))
+	}
+	_ = rep.Close()
+
+	stats := rep.Stats()
+	if stats.SpansDropped != 0 || int(stats.SpansReported)+rep.Spooled() != 1000 {
+		t.Errorf("expected every span either reported or spooled, got %+v with %d spooled", stats, rep.Spooled())
+	}
+	if rep.Spooled() == 0 {
+		t.Error("expected overflowing spans to be spooled")
+	}
+}
+
+func TestFullQueueWithoutSpoolDrops(t *testing.T) {
+	c := &collector{statuses: []int{http.StatusAccepted}}
+	rep := newTestReporter(t, c, Options{QueueSize: 1, BatchSize: 1000})
+
+	for i := uint64(1); i <= 1000; i++ {
+		rep.Send(span(i))
+	}
+	_ = rep.Close()
+
+	stats := rep.Stats()
+	if stats.SpansReported+stats.SpansDropped != 1000 || stats.SpansDropped == 0 {
+		t.Errorf("expected overflowing spans to be dropped, got %+v", stats)
+	}
+}
+
+func TestSendAfterCloseDropped(t *testing.T) {
+	c := &collector{statuses: []int{http.StatusAccepted}}
+	rep := newTestReporter(t, c, Options{})
+	_ = rep.Close()
+
+	rep.Send(span(1))
+
+	if rep.Dropped() != 1 || rep.QueueDepth() != 0 {
+		t.Errorf("expected the span to be dropped, got %d dropped and %d queued", rep.Dropped(), rep.QueueDepth())
+	}
+}
+
+func TestUnreadableSpoolFileRemoved(t *testing.T) {
+	dir := t.TempDir()
+
+	// Dangling link can be listed, yet never read
+	if err := os.Symlink(filepath.Join(dir, "missing"), filepath.Join(dir, "00000000000000000001-000001-3.json")); err != nil {
+		t.Skip("symlinks are not supported:", err)
+	}
+
+	s, err := newSpool(dir, ".json", DefaultMaxSpoolSize)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.write([]byte("[]"), 1); err != nil {
+		t.Fatal(err)
+	}
+
+	c := &collector{statuses: []int{http.StatusAccepted}}
+	rep := newTestReporter(t, c, Options{SpoolDir: dir, BatchInterval: 10 * time.Millisecond})
+
+	eventually(t, time.Second, func() bool {
+		return c.received() == 1
+	})
+	_ = rep.Close()
+
+	if rep.Dropped() != 3 {
+		t.Errorf("expected spans of the unreadable batch dropped, got %d", rep.Dropped())
+	}
+	if _, err := os.Lstat(filepath.Join(dir, "00000000000000000001-000001-3.json")); !os.IsNotExist(err) {
+		t.Errorf("expected the unreadable batch removed, got %v", err)
+	}
+}
+
+// msgpackSerializer pretends to encode batches with MessagePack
+type msgpackSerializer struct{}
+
+func (msgpackSerializer) Serialize(spans []*model.SpanModel) ([]byte, error) {
+	return []byte{0x90}, nil
+}
+
+func (msgpackSerializer) ContentType() string {
+	return "application/msgpack"
+}
+
+func TestSpoolFilesNamedAfterSerializer(t *testing.T) {
+	dir := t.TempDir()
+
+	// Batches of another serializer are left alone
+	if err := ioutil.WriteFile(filepath.Join(dir, "00000000000000000001-000001-1.json"), []byte("[]"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	c := &collector{statuses: []int{http.StatusServiceUnavailable}}
+	rep := newTestReporter(t, c, Options{SpoolDir: dir, Serializer: msgpackSerializer{}, BatchSize: 1})
+
+	rep.Send(span(1))
+	_ = rep.Close()
+
+	msgpack, _ := filepath.Glob(filepath.Join(dir, "*.msgpack"))
+	if len(msgpack) != 1 || rep.Spooled() != 1 {
+		t.Errorf("expected 1 spooled msgpack batch, got %v with %d spooled", msgpack, rep.Spooled())
+	}
+
+	leftovers, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+	if len(leftovers) != 1 {
+		t.Errorf("expected the json batch left alone, got %v", leftovers)
+	}
+}
@@ -0,0 +1,177 @@
+package buffered
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrSpoolFull is returned when spooling the batch would exceed the maximum spool size
+	ErrSpoolFull = errors.New("spool directory is full")
+)
+
+// spool is a file-based queue of serialized batches. Every batch is stored in its own
+// file named after the time it was spooled and the number of spans it holds. The file extension
+// reflects the serializer, so that batches of another serializer are never replayed.
+type spool struct {
+	dir       string
+	extension string
+	maxSize   int64
+
+	mu      sync.Mutex
+	size    int64
+	count   int
+	counter uint64
+	// quarantined lists batches that could neither be read nor removed, they are skipped
+	quarantined map[string]struct{}
+}
+
+func newSpool(dir string, extension string, maxSize int64) (*spool, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	s := &spool{dir: dir, extension: extension, maxSize: maxSize, quarantined: make(map[string]struct{})}
+
+	// Account for batches left over by the previous run, they are going to be replayed
+	files, err := s.files()
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range files {
+		s.size += file.Size()
+		s.count += spanCount(file.Name())
+	}
+
+	return s, nil
+}
+
+func (s *spool) write(body []byte, count int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.size+int64(len(body)) > s.maxSize {
+		return ErrSpoolFull
+	}
+
+	s.counter++
+	name := fmt.Sprintf("%020d-%06d-%d%s", time.Now().UnixNano(), s.counter%1000000, count, s.extension)
+	tmp := filepath.Join(s.dir, name+".tmp")
+
+	if err := ioutil.WriteFile(tmp, body, 0644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, filepath.Join(s.dir, name)); err != nil {
+		return err
+	}
+
+	s.size += int64(len(body))
+	s.count += count
+
+	return nil
+}
+
+// oldest reads the batch that was spooled first, nil body means the spool is empty.
+// The name is returned along with the error when the batch can't be read
+func (s *spool) oldest() (string, []byte, error) {
+	files, err := s.files()
+	if err != nil || len(files) == 0 {
+		return "", nil, err
+	}
+
+	name := files[0].Name()
+	body, err := ioutil.ReadFile(filepath.Join(s.dir, name))
+	if err != nil {
+		return name, nil, err
+	}
+
+	return name, body, nil
+}
+
+// remove deletes the batch, a batch that can't be deleted is quarantined so that it is never replayed again
+func (s *spool) remove(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	path := filepath.Join(s.dir, name)
+	info, err := os.Lstat(path)
+	if err != nil && os.IsNotExist(err) {
+		return
+	}
+
+	if err == nil && os.Remove(path) == nil {
+		s.size -= info.Size()
+	} else {
+		s.quarantined[name] = struct{}{}
+	}
+	s.count -= spanCount(name)
+}
+
+func (s *spool) spans() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.count
+}
+
+func (s *spool) files() ([]os.FileInfo, error) {
+	entries, err := ioutil.ReadDir(s.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	files := make([]os.FileInfo, 0, len(entries))
+	for _, entry := range entries {
+		if _, ok := s.quarantined[entry.Name()]; ok {
+			continue
+		}
+
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), s.extension) {
+			files = append(files, entry)
+		}
+	}
+
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].Name() < files[j].Name()
+	})
+
+	return files, nil
+}
+
+// spanCount parses the number of spans from the batch file name
+func spanCount(name string) int {
+	name = strings.TrimSuffix(name, filepath.Ext(name))
+	count, err := strconv.Atoi(name[strings.LastIndex(name, "-")+1:])
+	if err != nil {
+		return 0
+	}
+
+	return count
+}
+
+// extension resolves the extension of batch files from the content-type of the serializer
+func extension(contentType string) string {
+	contentType = strings.ToLower(contentType)
+
+	switch {
+	case strings.Contains(contentType, "json"):
+		return ".json"
+	case strings.Contains(contentType, "msgpack"):
+		return ".msgpack"
+	case strings.Contains(contentType, "protobuf"):
+		return ".pb"
+	}
+
+	return ".batch"
+}
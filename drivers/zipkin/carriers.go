@@ -0,0 +1,9 @@
+package zipkin
+
+// Adapters of the carriers the default formats are documented to accept
+import (
+	_ "github.com/Vinelab/tracing-go/carriers/amqp"
+	_ "github.com/Vinelab/tracing-go/carriers/aws"
+	_ "github.com/Vinelab/tracing-go/carriers/nats"
+	_ "github.com/Vinelab/tracing-go/carriers/pubsub"
+)
@@ -0,0 +1,103 @@
+package zipkin
+
+import (
+	"github.com/Vinelab/tracing-go"
+	"github.com/Vinelab/tracing-go/carriers"
+	"github.com/Vinelab/tracing-go/drivers/zipkin/propagation"
+	zipkinpropagation "github.com/openzipkin/zipkin-go/propagation"
+)
+
+// CarrierExtractor manages trace extraction in a single header style from any carrier
+// that can be adapted to tracing.TextMapReader (see carriers.NewReader)
+type CarrierExtractor struct {
+	TracerSetter
+	extract func(reader tracing.TextMapReader) zipkinpropagation.Extractor
+	baggage func(reader tracing.TextMapReader) map[string]string
+}
+
+// NewCarrierExtractor returns the instance of CarrierExtractor reading trace context with given header style,
+// e.g. propagation.ExtractB3
+func NewCarrierExtractor(extract func(reader tracing.TextMapReader) zipkinpropagation.Extractor) *CarrierExtractor {
+	return &CarrierExtractor{extract: extract}
+}
+
+// NewB3Extractor returns the instance of CarrierExtractor reading B3 headers, either single or multiple ones
+func NewB3Extractor() *CarrierExtractor {
+	return NewCarrierExtractor(propagation.ExtractB3)
+}
+
+// NewW3CExtractor returns the instance of CarrierExtractor reading W3C traceparent header
+func NewW3CExtractor() *CarrierExtractor {
+	return NewCarrierExtractor(propagation.ExtractW3C)
+}
+
+// NewJaegerExtractor returns the instance of CarrierExtractor reading Jaeger's uber-trace-id header
+// along with baggage items of uberctx-* headers
+func NewJaegerExtractor() *CarrierExtractor {
+	extractor := NewCarrierExtractor(propagation.ExtractJaeger)
+	extractor.baggage = propagation.ExtractJaegerBaggage
+	return extractor
+}
+
+// NewXRayExtractor returns the instance of CarrierExtractor reading AWS X-Ray X-Amzn-Trace-Id header
+func NewXRayExtractor() *CarrierExtractor {
+	return NewCarrierExtractor(propagation.ExtractXRay)
+}
+
+// NewDatadogExtractor returns the instance of CarrierExtractor reading Datadog headers
+func NewDatadogExtractor() *CarrierExtractor {
+	return NewCarrierExtractor(propagation.ExtractDatadog)
+}
+
+// Extract deserializes SpanContext from the carrier
+func (extractor *CarrierExtractor) Extract(carrier interface{}) (tracing.SpanContext, error) {
+	reader, err := carriers.NewReader(carrier)
+	if err != nil {
+		return nil, err
+	}
+
+	// Zero value of the extractor reads B3 headers
+	extract := extractor.extract
+	if extract == nil {
+		extract = propagation.ExtractB3
+	}
+
+	spanCtx := NewSpanContext(extractor.Tracing.Extract(extract(reader)))
+	if extractor.baggage != nil {
+		spanCtx.baggage = extractor.baggage(reader)
+	}
+
+	return spanCtx, nil
+}
+
+// TextMapExtractor manages trace extraction from TextMap carrier in B3 format
+type TextMapExtractor = CarrierExtractor
+
+// NewTextMapExtractor returns the instance of TextMapExtractor
+func NewTextMapExtractor() *TextMapExtractor {
+	return NewB3Extractor()
+}
+
+// HTTPExtractor manages trace extraction from HTTP carrier in B3 format
+type HTTPExtractor = CarrierExtractor
+
+// NewHTTPExtractor returns the instance of HTTPExtractor
+func NewHTTPExtractor() *HTTPExtractor {
+	return NewB3Extractor()
+}
+
+// AMQPExtractor manages trace extraction from AMQP carrier in B3 format
+type AMQPExtractor = CarrierExtractor
+
+// NewAMQPExtractor returns the instance of AMQPExtractor
+func NewAMQPExtractor() *AMQPExtractor {
+	return NewB3Extractor()
+}
+
+// GooglePubSubExtractor manages trace extraction from Google Cloud PubSub carrier in B3 format
+type GooglePubSubExtractor = CarrierExtractor
+
+// NewGooglePubSubExtractor returns the instance of GooglePubSubExtractor
+func NewGooglePubSubExtractor() *GooglePubSubExtractor {
+	return NewB3Extractor()
+}
@@ -0,0 +1,116 @@
+package zipkin
+
+import (
+	"github.com/Vinelab/tracing-go"
+	"github.com/Vinelab/tracing-go/carriers"
+	"github.com/Vinelab/tracing-go/drivers/zipkin/propagation"
+	zipkinpropagation "github.com/openzipkin/zipkin-go/propagation"
+)
+
+// CarrierInjector manages trace injection in a single header style into any carrier
+// that can be adapted to tracing.TextMapWriter (see carriers.NewWriter)
+type CarrierInjector struct {
+	inject  func(writer tracing.TextMapWriter) zipkinpropagation.Injector
+	baggage func(writer tracing.TextMapWriter, baggage map[string]string)
+}
+
+// NewCarrierInjector returns the instance of CarrierInjector writing trace context with given header style,
+// e.g. propagation.InjectB3
+func NewCarrierInjector(inject func(writer tracing.TextMapWriter) zipkinpropagation.Injector) *CarrierInjector {
+	return &CarrierInjector{inject: inject}
+}
+
+// NewB3Injector returns the instance of CarrierInjector writing B3 headers
+func NewB3Injector() *CarrierInjector {
+	return NewCarrierInjector(propagation.InjectB3)
+}
+
+// NewB3SingleInjector returns the instance of CarrierInjector writing single b3 header
+func NewB3SingleInjector() *CarrierInjector {
+	return NewCarrierInjector(propagation.InjectB3Single)
+}
+
+// NewMessageAttributesInjector returns the instance of CarrierInjector writing B3 headers into SQS or SNS
+// message attributes, packed into single b3 attribute when the message would exceed the attributes limit
+func NewMessageAttributesInjector() *CarrierInjector {
+	return NewCarrierInjector(propagation.InjectMessageAttributes)
+}
+
+// NewW3CInjector returns the instance of CarrierInjector writing W3C traceparent header
+func NewW3CInjector() *CarrierInjector {
+	return NewCarrierInjector(propagation.InjectW3C)
+}
+
+// NewJaegerInjector returns the instance of CarrierInjector writing Jaeger's uber-trace-id header
+// along with baggage items as uberctx-* headers
+func NewJaegerInjector() *CarrierInjector {
+	injector := NewCarrierInjector(propagation.InjectJaeger)
+	injector.baggage = propagation.InjectJaegerBaggage
+	return injector
+}
+
+// NewXRayInjector returns the instance of CarrierInjector writing AWS X-Ray X-Amzn-Trace-Id header
+func NewXRayInjector() *CarrierInjector {
+	return NewCarrierInjector(propagation.InjectXRay)
+}
+
+// NewDatadogInjector returns the instance of CarrierInjector writing Datadog headers
+func NewDatadogInjector() *CarrierInjector {
+	return NewCarrierInjector(propagation.InjectDatadog)
+}
+
+// Inject serialises given SpanContext into the carrier
+func (injector *CarrierInjector) Inject(spanCtx tracing.SpanContext, carrier interface{}) error {
+	writer, err := carriers.NewWriter(carrier)
+	if err != nil {
+		return err
+	}
+
+	// Zero value of the injector writes B3 headers
+	inject := injector.inject
+	if inject == nil {
+		inject = propagation.InjectB3
+	}
+
+	if err := inject(writer)(zipkinRawContext(spanCtx)); err != nil {
+		return err
+	}
+
+	if zipkinCtx, ok := spanCtx.(*SpanContext); ok && injector.baggage != nil && len(zipkinCtx.baggage) > 0 {
+		injector.baggage(writer, zipkinCtx.baggage)
+	}
+
+	return nil
+}
+
+// TextMapInjector manages trace injection into TextMap carrier in B3 format
+type TextMapInjector = CarrierInjector
+
+// NewTextMapInjector returns the instance of TextMapInjector
+func NewTextMapInjector() *TextMapInjector {
+	return NewB3Injector()
+}
+
+// HTTPInjector manages trace injection into HTTP carrier in B3 format
+type HTTPInjector = CarrierInjector
+
+// NewHTTPInjector returns the instance of HTTPInjector
+func NewHTTPInjector() *HTTPInjector {
+	return NewB3Injector()
+}
+
+// AMQPInjector manages trace injection into AMQP carrier in B3 format
+type AMQPInjector = CarrierInjector
+
+// NewAMQPInjector returns the instance of AMQPInjector
+func NewAMQPInjector() *AMQPInjector {
+	return NewB3Injector()
+}
+
+// GooglePubSubInjector manages trace injection into Google Cloud PubSub carrier in B3 format
+type GooglePubSubInjector = CarrierInjector
+
+// NewGooglePubSubInjector returns the instance of GooglePubSubInjector
+func NewGooglePubSubInjector() *GooglePubSubInjector {
+	return NewB3Injector()
+}
@@ -0,0 +1,86 @@
+package propagation
+
+import (
+	"errors"
+
+	"github.com/Vinelab/tracing-go"
+	"github.com/openzipkin/zipkin-go/model"
+	"github.com/openzipkin/zipkin-go/propagation"
+	"github.com/openzipkin/zipkin-go/propagation/b3"
+)
+
+// MaxMessageAttributes is the maximum number of attributes SQS and SNS accept per message
+const MaxMessageAttributes = 10
+
+var (
+	// ErrTooManyMessageAttributes is returned if the message has no room left for the trace context attribute
+	ErrTooManyMessageAttributes = errors.New("too many message attributes to inject trace context")
+)
+
+// InjectMessageAttributes will inject a span.Context into SQS or SNS message attributes in B3 header format.
+// The context is packed into single b3 attribute when X-B3-* attributes would exceed MaxMessageAttributes,
+// nothing is injected when even the single attribute would exceed it. Attributes of the other encoding
+// left over from a previous injection are removed when the carrier supports it (see carriers/aws).
+func InjectMessageAttributes(writer tracing.TextMapWriter) propagation.Injector {
+	return func(sc model.SpanContext) error {
+		reader, ok := writer.(tracing.TextMapReader)
+		if !ok {
+			return InjectB3(writer)(sc)
+		}
+
+		count := 0
+		for _, key := range reader.Keys() {
+			switch key {
+			case b3.TraceID, b3.SpanID, b3.ParentSpanID, b3.Sampled, b3.Flags, b3.Context:
+			default:
+				count++
+			}
+		}
+
+		switch {
+		case count+multiHeaderCount(sc) <= MaxMessageAttributes:
+			deleteKeys(writer, b3.Context)
+			return InjectB3(writer)(sc)
+		case count < MaxMessageAttributes:
+			deleteKeys(writer, b3.TraceID, b3.SpanID, b3.ParentSpanID, b3.Sampled, b3.Flags)
+			return InjectB3Single(writer)(sc)
+		}
+
+		return ErrTooManyMessageAttributes
+	}
+}
+
+// keyDeleter is implemented by carriers able to remove keys, i.e. SQS and SNS message attributes
+type keyDeleter interface {
+	Del(key string)
+}
+
+// deleteKeys removes attributes of the other B3 encoding left over from a previous injection,
+// so that the message does not carry two contexts
+func deleteKeys(writer tracing.TextMapWriter, keys ...string) {
+	deleter, ok := writer.(keyDeleter)
+	if !ok {
+		return
+	}
+
+	for _, key := range keys {
+		deleter.Del(key)
+	}
+}
+
+// multiHeaderCount returns the number of X-B3-* headers InjectB3 writes for the span context
+func multiHeaderCount(sc model.SpanContext) int {
+	count := 0
+	if sc.Debug || sc.Sampled != nil {
+		count++
+	}
+
+	if !sc.TraceID.Empty() && sc.ID > 0 {
+		count += 2
+		if sc.ParentID != nil {
+			count++
+		}
+	}
+
+	return count
+}
@@ -0,0 +1,149 @@
+package propagation
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/Vinelab/tracing-go"
+	"github.com/Vinelab/tracing-go/carriers"
+	_ "github.com/Vinelab/tracing-go/carriers/aws"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sns"
+	snstypes "github.com/aws/aws-sdk-go-v2/service/sns/types"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	sqstypes "github.com/aws/aws-sdk-go-v2/service/sqs/types"
+	"github.com/openzipkin/zipkin-go/model"
+	"github.com/openzipkin/zipkin-go/propagation/b3"
+)
+
+func testSpanContext() model.SpanContext {
+	sampled := true
+	parentID := model.ID(1)
+
+	return model.SpanContext{
+		TraceID:  model.TraceID{Low: 42},
+		ID:       model.ID(2),
+		ParentID: &parentID,
+		Sampled:  &sampled,
+	}
+}
+
+func sqsAttributes(count int) map[string]sqstypes.MessageAttributeValue {
+	attributes := make(map[string]sqstypes.MessageAttributeValue)
+	for i := 0; i < count; i++ {
+		attributes[fmt.Sprintf("attribute-%d", i)] = sqstypes.MessageAttributeValue{
+			DataType:    aws.String("String"),
+			StringValue: aws.String("value"),
+		}
+	}
+
+	return attributes
+}
+
+func snsAttributes(count int) map[string]snstypes.MessageAttributeValue {
+	attributes := make(map[string]snstypes.MessageAttributeValue)
+	for i := 0; i < count; i++ {
+		attributes[fmt.Sprintf("attribute-%d", i)] = snstypes.MessageAttributeValue{
+			DataType:    aws.String("String"),
+			StringValue: aws.String("value"),
+		}
+	}
+
+	return attributes
+}
+
+func TestInjectMessageAttributes(t *testing.T) {
+	cases := []struct {
+		existing int
+		single   bool
+		err      error
+	}{
+		{existing: 0},
+		{existing: 6},
+		{existing: 7, single: true},
+		{existing: 9, single: true},
+		{existing: 10, err: ErrTooManyMessageAttributes},
+	}
+
+	for _, c := range cases {
+		carriersOf := map[string]interface{}{
+			"sqs": &sqs.SendMessageInput{MessageAttributes: sqsAttributes(c.existing)},
+			"sns": &sns.PublishInput{MessageAttributes: snsAttributes(c.existing)},
+		}
+
+		for name, carrier := range carriersOf {
+			writer, err := carriers.NewWriter(carrier)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = InjectMessageAttributes(writer)(testSpanContext())
+			if err != c.err {
+				t.Errorf("%s with %d attributes: expected error %v, got %v", name, c.existing, c.err, err)
+			}
+
+			reader := writer.(tracing.TextMapReader)
+			keys := reader.Keys()
+			if len(keys) > MaxMessageAttributes {
+				t.Errorf("%s with %d attributes: expected at most %d attributes, got %d", name, c.existing, MaxMessageAttributes, len(keys))
+			}
+
+			switch {
+			case c.err != nil:
+				if len(keys) != c.existing {
+					t.Errorf("%s with %d attributes: expected nothing injected, got %v", name, c.existing, keys)
+				}
+			case c.single:
+				if reader.Get(b3.Context) == "" || reader.Get(b3.TraceID) != "" {
+					t.Errorf("%s with %d attributes: expected single b3 attribute, got %v", name, c.existing, keys)
+				}
+			default:
+				if reader.Get(b3.TraceID) == "" || reader.Get(b3.Context) != "" {
+					t.Errorf("%s with %d attributes: expected X-B3-* attributes, got %v", name, c.existing, keys)
+				}
+			}
+
+			if c.err == nil {
+				sc, err := ExtractB3(reader)()
+				if err != nil || sc.TraceID != testSpanContext().TraceID || sc.ID != testSpanContext().ID {
+					t.Errorf("%s with %d attributes: expected extractable context, got %v (%v)", name, c.existing, sc, err)
+				}
+			}
+		}
+	}
+}
+
+func TestReinjectMessageAttributesClearsOtherEncoding(t *testing.T) {
+	input := &sqs.SendMessageInput{MessageAttributes: sqsAttributes(6)}
+
+	inject := func() tracing.TextMapReader {
+		t.Helper()
+
+		writer, err := carriers.NewWriter(input)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := InjectMessageAttributes(writer)(testSpanContext()); err != nil {
+			t.Fatal(err)
+		}
+
+		return writer.(tracing.TextMapReader)
+	}
+
+	if reader := inject(); reader.Get(b3.TraceID) == "" {
+		t.Fatalf("expected X-B3-* attributes, got %v", reader.Keys())
+	}
+
+	// The message grows past the room for X-B3-* attributes, i.e. when it is retried
+	input.MessageAttributes["attribute-6"] = input.MessageAttributes["attribute-0"]
+	reader := inject()
+	if reader.Get(b3.Context) == "" || len(reader.Keys()) != 8 {
+		t.Errorf("expected X-B3-* attributes replaced by single b3 attribute, got %v", reader.Keys())
+	}
+
+	delete(input.MessageAttributes, "attribute-6")
+	reader = inject()
+	if reader.Get(b3.TraceID) == "" || reader.Get(b3.Context) != "" || len(reader.Keys()) != 10 {
+		t.Errorf("expected b3 attribute replaced by X-B3-* attributes, got %v", reader.Keys())
+	}
+}
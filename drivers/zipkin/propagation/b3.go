@@ -0,0 +1,89 @@
+package propagation
+
+import (
+	"github.com/Vinelab/tracing-go"
+	"github.com/openzipkin/zipkin-go/model"
+	"github.com/openzipkin/zipkin-go/propagation"
+	"github.com/openzipkin/zipkin-go/propagation/b3"
+)
+
+// ExtractB3 will extract a span.Context from the carrier if found in B3 header format.
+// Single b3 header takes precedence over multiple X-B3-* headers.
+func ExtractB3(reader tracing.TextMapReader) propagation.Extractor {
+	return func() (*model.SpanContext, error) {
+		var (
+			traceIDHeader      = reader.Get(b3.TraceID)
+			spanIDHeader       = reader.Get(b3.SpanID)
+			parentSpanIDHeader = reader.Get(b3.ParentSpanID)
+			sampledHeader      = reader.Get(b3.Sampled)
+			flagsHeader        = reader.Get(b3.Flags)
+			singleHeader       = reader.Get(b3.Context)
+		)
+
+		var (
+			sc   *model.SpanContext
+			sErr error
+			mErr error
+		)
+		if singleHeader != "" {
+			sc, sErr = b3.ParseSingleHeader(singleHeader)
+			if sErr == nil {
+				return sc, nil
+			}
+		}
+
+		sc, mErr = b3.ParseHeaders(
+			traceIDHeader, spanIDHeader, parentSpanIDHeader, sampledHeader,
+			flagsHeader,
+		)
+
+		if mErr != nil && sErr != nil {
+			return nil, sErr
+		}
+
+		return sc, mErr
+	}
+}
+
+// InjectB3 will inject a span.Context into the carrier in B3 header format
+func InjectB3(writer tracing.TextMapWriter) propagation.Injector {
+	return func(sc model.SpanContext) error {
+		if (model.SpanContext{}) == sc {
+			return b3.ErrEmptyContext
+		}
+
+		if sc.Debug {
+			writer.Set(b3.Flags, "1")
+		} else if sc.Sampled != nil {
+			// Debug is encoded as X-B3-Flags: 1. Since Debug implies Sampled,
+			// so don't also send "X-B3-Sampled: 1".
+			if *sc.Sampled {
+				writer.Set(b3.Sampled, "1")
+			} else {
+				writer.Set(b3.Sampled, "0")
+			}
+		}
+
+		if !sc.TraceID.Empty() && sc.ID > 0 {
+			writer.Set(b3.TraceID, sc.TraceID.String())
+			writer.Set(b3.SpanID, sc.ID.String())
+			if sc.ParentID != nil {
+				writer.Set(b3.ParentSpanID, sc.ParentID.String())
+			}
+		}
+
+		return nil
+	}
+}
+
+// InjectB3Single will inject a span.Context into the carrier as single b3 header
+func InjectB3Single(writer tracing.TextMapWriter) propagation.Injector {
+	return func(sc model.SpanContext) error {
+		if (model.SpanContext{}) == sc {
+			return b3.ErrEmptyContext
+		}
+
+		writer.Set(b3.Context, b3.BuildSingleHeader(sc))
+		return nil
+	}
+}
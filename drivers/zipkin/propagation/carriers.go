@@ -0,0 +1,59 @@
+package propagation
+
+import (
+	"net/http"
+
+	"cloud.google.com/go/pubsub"
+	"github.com/Vinelab/tracing-go/carriers"
+	amqpcarrier "github.com/Vinelab/tracing-go/carriers/amqp"
+	"github.com/openzipkin/zipkin-go/propagation"
+	"github.com/streadway/amqp"
+)
+
+// ExtractHTTP will extract a span.Context from the HTTP Request if found in B3 header format.
+func ExtractHTTP(r *http.Request) propagation.Extractor {
+	return ExtractB3(carriers.HTTPHeader(r.Header))
+}
+
+// InjectHTTP will inject a span.Context into a HTTP Request
+func InjectHTTP(r *http.Request) propagation.Injector {
+	return InjectB3(carriers.HTTPHeader(r.Header))
+}
+
+// ExtractTextMap will extract a span.Context from the string map if found in B3 header format.
+func ExtractTextMap(dict map[string]string) propagation.Extractor {
+	return ExtractB3(carriers.TextMap(dict))
+}
+
+// InjectTextMap will inject a span.Context into a string map
+func InjectTextMap(dict map[string]string) propagation.Injector {
+	return InjectB3(carriers.TextMap(dict))
+}
+
+// ExtractAMQP will extract a span.Context from the AMQP Message if found in B3 header format.
+func ExtractAMQP(msg *amqp.Delivery) propagation.Extractor {
+	return ExtractB3(amqpcarrier.Table(msg.Headers))
+}
+
+// InjectAMQP will inject a span.Context into a AMQP message
+func InjectAMQP(msg *amqp.Publishing) propagation.Injector {
+	if msg.Headers == nil {
+		msg.Headers = amqp.Table{}
+	}
+
+	return InjectB3(amqpcarrier.Table(msg.Headers))
+}
+
+// ExtractGooglePubSub will extract a span.Context from the Google Cloud PubSub message if found in B3 header format.
+func ExtractGooglePubSub(msg *pubsub.Message) propagation.Extractor {
+	return ExtractB3(carriers.TextMap(msg.Attributes))
+}
+
+// InjectGooglePubSub will inject a span.Context into a Google Cloud PubSub message
+func InjectGooglePubSub(msg *pubsub.Message) propagation.Injector {
+	if msg.Attributes == nil {
+		msg.Attributes = make(map[string]string)
+	}
+
+	return InjectB3(carriers.TextMap(msg.Attributes))
+}
@@ -0,0 +1,121 @@
+package propagation
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Vinelab/tracing-go"
+	"github.com/openzipkin/zipkin-go/model"
+	"github.com/openzipkin/zipkin-go/propagation"
+	"github.com/openzipkin/zipkin-go/propagation/b3"
+)
+
+// Datadog header keys
+const (
+	DatadogTraceID          = "x-datadog-trace-id"
+	DatadogParentID         = "x-datadog-parent-id"
+	DatadogSamplingPriority = "x-datadog-sampling-priority"
+	DatadogTags             = "x-datadog-tags"
+	// DatadogTraceIDHigh is the propagation tag holding upper 64 bits of 128 bit trace ID in hex
+	DatadogTraceIDHigh = "_dd.p.tid"
+)
+
+// Datadog header extraction errors
+var (
+	ErrInvalidDatadogTraceIDHeader          = errors.New("invalid x-datadog-trace-id header found")
+	ErrInvalidDatadogParentIDHeader         = errors.New("invalid x-datadog-parent-id header found")
+	ErrInvalidDatadogSamplingPriorityHeader = errors.New("invalid x-datadog-sampling-priority header found")
+)
+
+// ParseDatadogHeaders converts Datadog headers holding decimal 64 bit IDs into a span context.
+// Upper 64 bits of 128 bit trace ID are restored from _dd.p.tid tag of x-datadog-tags header.
+func ParseDatadogHeaders(traceIDHeader, parentIDHeader, samplingPriorityHeader, tagsHeader string) (*model.SpanContext, error) {
+	// Missing header means there is no trace to continue, which is not an error
+	if traceIDHeader == "" && parentIDHeader == "" {
+		return nil, nil
+	}
+
+	traceIDLow, err := strconv.ParseUint(traceIDHeader, 10, 64)
+	if err != nil || traceIDLow == 0 {
+		return nil, ErrInvalidDatadogTraceIDHeader
+	}
+
+	spanID, err := strconv.ParseUint(parentIDHeader, 10, 64)
+	if err != nil || spanID == 0 {
+		return nil, ErrInvalidDatadogParentIDHeader
+	}
+
+	sc := &model.SpanContext{
+		TraceID: model.TraceID{Low: traceIDLow},
+		ID:      model.ID(spanID),
+	}
+
+	for _, tag := range strings.Split(tagsHeader, ",") {
+		pair := strings.SplitN(strings.TrimSpace(tag), "=", 2)
+		if len(pair) != 2 || pair[0] != DatadogTraceIDHigh || len(pair[1]) != 16 {
+			continue
+		}
+
+		// Malformed propagation tags are ignored by Datadog tracers as well
+		if high, err := strconv.ParseUint(pair[1], 16, 64); err == nil {
+			sc.TraceID.High = high
+		}
+	}
+
+	// Priorities -1 (user reject) and 0 (auto reject) drop the trace, 1 (auto keep) and 2 (user keep) keep it
+	if samplingPriorityHeader != "" {
+		priority, err := strconv.Atoi(samplingPriorityHeader)
+		if err != nil {
+			return nil, ErrInvalidDatadogSamplingPriorityHeader
+		}
+
+		sampled := priority > 0
+		sc.Sampled = &sampled
+	}
+
+	return sc, nil
+}
+
+// ExtractDatadog will extract a span.Context from the carrier if found in Datadog header format.
+func ExtractDatadog(reader tracing.TextMapReader) propagation.Extractor {
+	return func() (*model.SpanContext, error) {
+		return ParseDatadogHeaders(
+			reader.Get(DatadogTraceID), reader.Get(DatadogParentID),
+			reader.Get(DatadogSamplingPriority), reader.Get(DatadogTags),
+		)
+	}
+}
+
+// InjectDatadog will inject a span.Context into the carrier in Datadog header format
+func InjectDatadog(writer tracing.TextMapWriter) propagation.Injector {
+	return func(sc model.SpanContext) error {
+		if (model.SpanContext{}) == sc {
+			return b3.ErrEmptyContext
+		}
+
+		if sc.TraceID.Empty() || sc.ID == 0 {
+			return ErrInvalidDatadogTraceIDHeader
+		}
+
+		writer.Set(DatadogTraceID, strconv.FormatUint(sc.TraceID.Low, 10))
+		writer.Set(DatadogParentID, strconv.FormatUint(uint64(sc.ID), 10))
+
+		if sc.Debug {
+			writer.Set(DatadogSamplingPriority, "2")
+		} else if sc.Sampled != nil {
+			if *sc.Sampled {
+				writer.Set(DatadogSamplingPriority, "1")
+			} else {
+				writer.Set(DatadogSamplingPriority, "0")
+			}
+		}
+
+		if sc.TraceID.High != 0 {
+			writer.Set(DatadogTags, fmt.Sprintf("%s=%016x", DatadogTraceIDHigh, sc.TraceID.High))
+		}
+
+		return nil
+	}
+}
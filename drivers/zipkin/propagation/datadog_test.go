@@ -0,0 +1,142 @@
+package propagation
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/Vinelab/tracing-go/carriers"
+	"github.com/openzipkin/zipkin-go/model"
+)
+
+func TestDatadogRoundTrip128BitTraceID(t *testing.T) {
+	sampled := true
+	sc := model.SpanContext{
+		TraceID: model.TraceID{High: 0x640cfd8d00000000, Low: 0xabcdef0123456789},
+		ID:      model.ID(42),
+		Sampled: &sampled,
+	}
+
+	header := http.Header{}
+	writer, err := carriers.NewWriter(header)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := InjectDatadog(writer)(sc); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]string{
+		DatadogTraceID:          "12379813738877118345",
+		DatadogParentID:         "42",
+		DatadogSamplingPriority: "1",
+		DatadogTags:             "_dd.p.tid=640cfd8d00000000",
+	}
+	for key, value := range expected {
+		if header.Get(key) != value {
+			t.Errorf("%s: expected %q, got %q", key, value, header.Get(key))
+		}
+	}
+
+	reader, err := carriers.NewReader(header)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	extracted, err := ExtractDatadog(reader)()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if extracted.TraceID != sc.TraceID || extracted.ID != sc.ID || extracted.Sampled == nil || !*extracted.Sampled {
+		t.Errorf("expected %v, got %v", sc, extracted)
+	}
+}
+
+func TestInjectDatadog64BitTraceIDWithoutTags(t *testing.T) {
+	header := map[string]string{}
+	writer, err := carriers.NewWriter(header)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := InjectDatadog(writer)(model.SpanContext{TraceID: model.TraceID{Low: 7}, ID: 1, Debug: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := header[DatadogTags]; ok {
+		t.Errorf("expected no tags for 64 bit trace ID, got %v", header)
+	}
+	if header[DatadogSamplingPriority] != "2" {
+		t.Errorf("expected user keep priority for debug context, got %q", header[DatadogSamplingPriority])
+	}
+}
+
+func TestParseDatadogHeadersTags(t *testing.T) {
+	cases := map[string]uint64{
+		"_dd.p.tid=640cfd8d00000000":              0x640cfd8d00000000,
+		"_dd.p.dm=-4, _dd.p.tid=640cfd8d00000000": 0x640cfd8d00000000,
+		"_dd.p.tid=640cfd8d":                      0,
+		"_dd.p.tid=640cfd8d0000000z":              0,
+		"_dd.p.tid":                               0,
+		"_dd.p.dm=-4":                             0,
+		"":                                        0,
+	}
+
+	for tags, high := range cases {
+		sc, err := ParseDatadogHeaders("7", "1", "", tags)
+		if err != nil {
+			t.Fatalf("%q: %v", tags, err)
+		}
+		if sc.TraceID.High != high || sc.TraceID.Low != 7 {
+			t.Errorf("%q: expected trace high bits %x, got %v", tags, high, sc.TraceID)
+		}
+	}
+}
+
+func TestParseDatadogHeadersSamplingPriority(t *testing.T) {
+	cases := map[string]*bool{
+		"-1": &[]bool{false}[0],
+		"0":  &[]bool{false}[0],
+		"1":  &[]bool{true}[0],
+		"2":  &[]bool{true}[0],
+		"":   nil,
+	}
+
+	for priority, expected := range cases {
+		sc, err := ParseDatadogHeaders("7", "1", priority, "")
+		if err != nil {
+			t.Fatalf("%q: %v", priority, err)
+		}
+
+		switch {
+		case expected == nil && sc.Sampled != nil:
+			t.Errorf("%q: expected no sampling decision, got %v", priority, *sc.Sampled)
+		case expected != nil && (sc.Sampled == nil || *sc.Sampled != *expected):
+			t.Errorf("%q: expected sampled %v, got %v", priority, *expected, sc.Sampled)
+		}
+	}
+}
+
+func TestParseDatadogHeadersMalformed(t *testing.T) {
+	cases := []struct {
+		traceID, parentID, priority string
+		err                         error
+	}{
+		{traceID: "", parentID: "1", err: ErrInvalidDatadogTraceIDHeader},
+		{traceID: "abc", parentID: "1", err: ErrInvalidDatadogTraceIDHeader},
+		{traceID: "0", parentID: "1", err: ErrInvalidDatadogTraceIDHeader},
+		{traceID: "-7", parentID: "1", err: ErrInvalidDatadogTraceIDHeader},
+		{traceID: "18446744073709551616", parentID: "1", err: ErrInvalidDatadogTraceIDHeader},
+		{traceID: "7", parentID: "", err: ErrInvalidDatadogParentIDHeader},
+		{traceID: "7", parentID: "0", err: ErrInvalidDatadogParentIDHeader},
+		{traceID: "7", parentID: "0x1", err: ErrInvalidDatadogParentIDHeader},
+		{traceID: "7", parentID: "1", priority: "keep", err: ErrInvalidDatadogSamplingPriorityHeader},
+	}
+
+	for _, c := range cases {
+		sc, err := ParseDatadogHeaders(c.traceID, c.parentID, c.priority, "")
+		if err != c.err || sc != nil {
+			t.Errorf("%q/%q/%q: expected %v, got %v (%v)", c.traceID, c.parentID, c.priority, c.err, err, sc)
+		}
+	}
+}
@@ -0,0 +1,199 @@
+package propagation
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/Vinelab/tracing-go"
+	"github.com/openzipkin/zipkin-go/model"
+	"github.com/openzipkin/zipkin-go/propagation"
+	"github.com/openzipkin/zipkin-go/propagation/b3"
+)
+
+// Header keys Jaeger clients propagate trace context and baggage with
+const (
+	// UberTraceID carries trace context
+	UberTraceID = "uber-trace-id"
+	// UberBaggagePrefix prefixes headers carrying baggage items, e.g. uberctx-user-id
+	UberBaggagePrefix = "uberctx-"
+	// JaegerBaggage carries ad-hoc baggage items as comma-separated key=value pairs
+	JaegerBaggage = "jaeger-baggage"
+)
+
+// Jaeger flags encoded in the last segment of uber-trace-id
+const (
+	jaegerSampledFlag = 1
+	jaegerDebugFlag   = 2
+)
+
+// Jaeger header extraction errors
+var (
+	ErrInvalidUberTraceIDHeader = errors.New("invalid uber-trace-id header found")
+	ErrInvalidUberTraceIDValue  = errors.New("invalid uber-trace-id value found")
+)
+
+// ParseUberTraceID converts Jaeger's {trace-id}:{span-id}:{parent-id}:{flags} encoding into a span context
+func ParseUberTraceID(value string) (*model.SpanContext, error) {
+	// Missing header means there is no trace to continue, which is not an error
+	if value == "" {
+		return nil, nil
+	}
+
+	// Jaeger clients URL-encode the header value (i.e. "%3A" in place of colons)
+	if unescaped, err := url.QueryUnescape(value); err == nil {
+		value = unescaped
+	}
+
+	parts := strings.Split(value, ":")
+	if len(parts) != 4 {
+		return nil, ErrInvalidUberTraceIDHeader
+	}
+
+	if len(parts[0]) == 0 || len(parts[0]) > 32 {
+		return nil, ErrInvalidUberTraceIDValue
+	}
+
+	traceID, err := model.TraceIDFromHex(parts[0])
+	if err != nil || traceID.Empty() {
+		return nil, ErrInvalidUberTraceIDValue
+	}
+
+	spanID, err := parseJaegerID(parts[1])
+	if err != nil || spanID == 0 {
+		return nil, ErrInvalidUberTraceIDValue
+	}
+
+	parentID, err := parseJaegerID(parts[2])
+	if err != nil {
+		return nil, ErrInvalidUberTraceIDValue
+	}
+
+	flags, err := strconv.ParseUint(parts[3], 16, 8)
+	if err != nil {
+		return nil, ErrInvalidUberTraceIDValue
+	}
+
+	sampled := flags&jaegerSampledFlag == jaegerSampledFlag
+
+	sc := &model.SpanContext{
+		TraceID: traceID,
+		ID:      spanID,
+		Debug:   flags&jaegerDebugFlag == jaegerDebugFlag,
+		Sampled: &sampled,
+	}
+
+	// Jaeger encodes missing parent as 0
+	if parentID != 0 {
+		sc.ParentID = &parentID
+	}
+
+	if sc.Debug {
+		sc.Sampled = nil
+	}
+
+	return sc, nil
+}
+
+// BuildUberTraceID converts span context into Jaeger's {trace-id}:{span-id}:{parent-id}:{flags} encoding
+func BuildUberTraceID(sc model.SpanContext) (string, error) {
+	if (model.SpanContext{}) == sc {
+		return "", b3.ErrEmptyContext
+	}
+
+	if sc.TraceID.Empty() || sc.ID == 0 {
+		return "", ErrInvalidUberTraceIDValue
+	}
+
+	parentID := "0"
+	if sc.ParentID != nil {
+		parentID = sc.ParentID.String()
+	}
+
+	var flags uint8
+	if sc.Debug {
+		flags |= jaegerDebugFlag | jaegerSampledFlag
+	} else if sc.Sampled != nil && *sc.Sampled {
+		flags |= jaegerSampledFlag
+	}
+
+	return fmt.Sprintf("%s:%s:%s:%x", sc.TraceID, sc.ID, parentID, flags), nil
+}
+
+// ExtractJaeger will extract a span.Context from the carrier if found in uber-trace-id header.
+func ExtractJaeger(reader tracing.TextMapReader) propagation.Extractor {
+	return func() (*model.SpanContext, error) {
+		return ParseUberTraceID(reader.Get(UberTraceID))
+	}
+}
+
+// InjectJaeger will inject a span.Context into the carrier as uber-trace-id header
+func InjectJaeger(writer tracing.TextMapWriter) propagation.Injector {
+	return func(sc model.SpanContext) error {
+		value, err := BuildUberTraceID(sc)
+		if err != nil {
+			return err
+		}
+
+		writer.Set(UberTraceID, value)
+		return nil
+	}
+}
+
+// ExtractJaegerBaggage reads baggage items from uberctx-* headers and jaeger-baggage header.
+// Keys are lowercased since header keys are case-insensitive. Returns nil if there are none.
+func ExtractJaegerBaggage(reader tracing.TextMapReader) map[string]string {
+	var baggage map[string]string
+	set := func(key string, value string) {
+		if key == "" {
+			return
+		}
+
+		if baggage == nil {
+			baggage = make(map[string]string)
+		}
+		baggage[strings.ToLower(key)] = value
+	}
+
+	for _, pair := range strings.Split(reader.Get(JaegerBaggage), ",") {
+		kv := strings.SplitN(pair, "=", 2)
+		if len(kv) == 2 {
+			set(strings.TrimSpace(kv[0]), strings.TrimSpace(kv[1]))
+		}
+	}
+
+	// Items of uberctx-* headers take precedence over ad-hoc ones
+	for _, key := range reader.Keys() {
+		if len(key) <= len(UberBaggagePrefix) || !strings.EqualFold(key[:len(UberBaggagePrefix)], UberBaggagePrefix) {
+			continue
+		}
+
+		// Jaeger clients URL-encode values of baggage items
+		value := reader.Get(key)
+		if unescaped, err := url.QueryUnescape(value); err == nil {
+			value = unescaped
+		}
+
+		set(key[len(UberBaggagePrefix):], value)
+	}
+
+	return baggage
+}
+
+// InjectJaegerBaggage writes baggage items as uberctx-* headers with URL-encoded values
+func InjectJaegerBaggage(writer tracing.TextMapWriter, baggage map[string]string) {
+	for key, value := range baggage {
+		writer.Set(UberBaggagePrefix+key, url.QueryEscape(value))
+	}
+}
+
+func parseJaegerID(value string) (model.ID, error) {
+	if len(value) == 0 || len(value) > 16 {
+		return 0, ErrInvalidUberTraceIDValue
+	}
+
+	id, err := strconv.ParseUint(value, 16, 64)
+	return model.ID(id), err
+}
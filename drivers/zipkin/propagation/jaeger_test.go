@@ -0,0 +1,66 @@
+package propagation
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/Vinelab/tracing-go/carriers"
+)
+
+func TestMissingHeadersAreNotErrors(t *testing.T) {
+	parsers := map[string]func() error{
+		"jaeger": func() error { _, err := ParseUberTraceID(""); return err },
+		"w3c":    func() error { _, err := ParseTraceParent(""); return err },
+		"xray":   func() error { _, err := ParseXRayTraceHeader(""); return err },
+		"datadog": func() error {
+			_, err := ParseDatadogHeaders("", "", "", "")
+			return err
+		},
+	}
+
+	for name, parse := range parsers {
+		if err := parse(); err != nil {
+			t.Errorf("%s: expected no error for missing header, got %v", name, err)
+		}
+	}
+}
+
+func TestExtractJaegerBaggage(t *testing.T) {
+	header := http.Header{}
+	header.Set("Uberctx-User-Id", "42")
+	header.Set("Uberctx-Greeting", "hello%20world")
+	header.Set("Jaeger-Baggage", "user-id=7, tenant=acme")
+
+	reader, err := carriers.NewReader(header)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	baggage := ExtractJaegerBaggage(reader)
+	expected := map[string]string{"user-id": "42", "greeting": "hello world", "tenant": "acme"}
+
+	if len(baggage) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, baggage)
+	}
+
+	for key, value := range expected {
+		if baggage[key] != value {
+			t.Errorf("expected %s=%q, got %q", key, value, baggage[key])
+		}
+	}
+}
+
+func TestInjectJaegerBaggage(t *testing.T) {
+	header := http.Header{}
+
+	writer, err := carriers.NewWriter(header)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	InjectJaegerBaggage(writer, map[string]string{"greeting": "hello world"})
+
+	if value := header.Get("uberctx-greeting"); value != "hello+world" {
+		t.Errorf("expected URL-encoded value, got %q", value)
+	}
+}
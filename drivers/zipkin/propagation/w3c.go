@@ -0,0 +1,110 @@
+package propagation
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Vinelab/tracing-go"
+	"github.com/openzipkin/zipkin-go/model"
+	"github.com/openzipkin/zipkin-go/propagation"
+	"github.com/openzipkin/zipkin-go/propagation/b3"
+)
+
+// TraceParent is the header key W3C Trace Context propagates trace context with.
+// Vendor specific tracestate header is not supported and thus ignored.
+const TraceParent = "traceparent"
+
+// W3C trace flags encoded in the last segment of traceparent
+const w3cSampledFlag = 1
+
+// W3C header extraction errors
+var (
+	ErrInvalidTraceParentHeader = errors.New("invalid traceparent header found")
+	ErrInvalidTraceParentValue  = errors.New("invalid traceparent value found")
+)
+
+// ParseTraceParent converts W3C's {version}-{trace-id}-{parent-id}-{flags} encoding into a span context
+func ParseTraceParent(value string) (*model.SpanContext, error) {
+	// Missing header means there is no trace to continue, which is not an error
+	if value == "" {
+		return nil, nil
+	}
+
+	parts := strings.Split(strings.TrimSpace(value), "-")
+	if len(parts) < 4 || len(parts[0]) != 2 {
+		return nil, ErrInvalidTraceParentHeader
+	}
+
+	// Version ff is forbidden, while unknown future versions may append more segments
+	if parts[0] == "ff" || (parts[0] == "00" && len(parts) != 4) {
+		return nil, ErrInvalidTraceParentHeader
+	}
+
+	if len(parts[1]) != 32 || len(parts[2]) != 16 || len(parts[3]) != 2 {
+		return nil, ErrInvalidTraceParentValue
+	}
+
+	traceID, err := model.TraceIDFromHex(parts[1])
+	if err != nil || traceID.Empty() {
+		return nil, ErrInvalidTraceParentValue
+	}
+
+	spanID, err := strconv.ParseUint(parts[2], 16, 64)
+	if err != nil || spanID == 0 {
+		return nil, ErrInvalidTraceParentValue
+	}
+
+	flags, err := strconv.ParseUint(parts[3], 16, 8)
+	if err != nil {
+		return nil, ErrInvalidTraceParentValue
+	}
+
+	sampled := flags&w3cSampledFlag == w3cSampledFlag
+
+	return &model.SpanContext{
+		TraceID: traceID,
+		ID:      model.ID(spanID),
+		Sampled: &sampled,
+	}, nil
+}
+
+// BuildTraceParent converts span context into W3C's {version}-{trace-id}-{parent-id}-{flags} encoding.
+// 64-bit trace identifiers are left-padded with zeros.
+func BuildTraceParent(sc model.SpanContext) (string, error) {
+	if (model.SpanContext{}) == sc {
+		return "", b3.ErrEmptyContext
+	}
+
+	if sc.TraceID.Empty() || sc.ID == 0 {
+		return "", ErrInvalidTraceParentValue
+	}
+
+	var flags uint8
+	if sc.Debug || (sc.Sampled != nil && *sc.Sampled) {
+		flags |= w3cSampledFlag
+	}
+
+	return fmt.Sprintf("00-%016x%016x-%016x-%02x", sc.TraceID.High, sc.TraceID.Low, uint64(sc.ID), flags), nil
+}
+
+// ExtractW3C will extract a span.Context from the carrier if found in traceparent header.
+func ExtractW3C(reader tracing.TextMapReader) propagation.Extractor {
+	return func() (*model.SpanContext, error) {
+		return ParseTraceParent(reader.Get(TraceParent))
+	}
+}
+
+// InjectW3C will inject a span.Context into the carrier as traceparent header
+func InjectW3C(writer tracing.TextMapWriter) propagation.Injector {
+	return func(sc model.SpanContext) error {
+		value, err := BuildTraceParent(sc)
+		if err != nil {
+			return err
+		}
+
+		writer.Set(TraceParent, value)
+		return nil
+	}
+}
@@ -0,0 +1,135 @@
+package propagation
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Vinelab/tracing-go"
+	"github.com/openzipkin/zipkin-go/model"
+	"github.com/openzipkin/zipkin-go/propagation"
+	"github.com/openzipkin/zipkin-go/propagation/b3"
+)
+
+// XRayTraceHeader is the header key AWS X-Ray propagates trace context with
+const XRayTraceHeader = "x-amzn-trace-id"
+
+// X-Ray header extraction errors
+var (
+	ErrInvalidXRayTraceHeader = errors.New("invalid X-Amzn-Trace-Id header found")
+	ErrInvalidXRayRootValue   = errors.New("invalid X-Amzn-Trace-Id Root value found")
+	ErrInvalidXRayParentValue = errors.New("invalid X-Amzn-Trace-Id Parent value found")
+)
+
+// XRayTraceID converts Zipkin trace ID into X-Ray's 1-{epoch}-{random} encoding. X-Ray expects
+// the first 32 bits to hold the epoch time in seconds, see idgenerator.NewRandomTimestamped.
+func XRayTraceID(traceID model.TraceID) string {
+	return fmt.Sprintf("1-%08x-%08x%016x", traceID.High>>32, traceID.High&0xffffffff, traceID.Low)
+}
+
+// ParseXRayTraceHeader converts X-Ray's Root=1-xxxxxxxx-...;Parent=...;Sampled=1 encoding into a span context.
+// Headers without Parent (i.e. those set by load balancers) are treated as invalid
+// since the trace can't be continued from a known span.
+func ParseXRayTraceHeader(value string) (*model.SpanContext, error) {
+	// Missing header means there is no trace to continue, which is not an error
+	if value == "" {
+		return nil, nil
+	}
+
+	var root, parent, sampled string
+	for _, part := range strings.Split(value, ";") {
+		pair := strings.SplitN(strings.TrimSpace(part), "=", 2)
+		if len(pair) != 2 {
+			continue
+		}
+
+		switch pair[0] {
+		case "Root":
+			root = pair[1]
+		case "Parent":
+			parent = pair[1]
+		case "Sampled":
+			sampled = pair[1]
+		}
+	}
+
+	if root == "" || parent == "" {
+		return nil, ErrInvalidXRayTraceHeader
+	}
+
+	rootParts := strings.Split(root, "-")
+	if len(rootParts) != 3 || rootParts[0] != "1" || len(rootParts[1]) != 8 || len(rootParts[2]) != 24 {
+		return nil, ErrInvalidXRayRootValue
+	}
+
+	traceID, err := model.TraceIDFromHex(rootParts[1] + rootParts[2])
+	if err != nil || traceID.Empty() {
+		return nil, ErrInvalidXRayRootValue
+	}
+
+	if len(parent) != 16 {
+		return nil, ErrInvalidXRayParentValue
+	}
+
+	spanID, err := strconv.ParseUint(parent, 16, 64)
+	if err != nil || spanID == 0 {
+		return nil, ErrInvalidXRayParentValue
+	}
+
+	sc := &model.SpanContext{
+		TraceID: traceID,
+		ID:      model.ID(spanID),
+	}
+
+	// Sampled=? requests the sampling decision to be made downstream
+	switch sampled {
+	case "1":
+		sc.Sampled = &[]bool{true}[0]
+	case "0":
+		sc.Sampled = &[]bool{false}[0]
+	}
+
+	return sc, nil
+}
+
+// BuildXRayTraceHeader converts span context into X-Ray's Root=1-xxxxxxxx-...;Parent=...;Sampled=1 encoding
+func BuildXRayTraceHeader(sc model.SpanContext) (string, error) {
+	if (model.SpanContext{}) == sc {
+		return "", b3.ErrEmptyContext
+	}
+
+	if sc.TraceID.Empty() || sc.ID == 0 {
+		return "", ErrInvalidXRayTraceHeader
+	}
+
+	value := fmt.Sprintf("Root=%s;Parent=%s", XRayTraceID(sc.TraceID), sc.ID)
+
+	if sc.Debug || (sc.Sampled != nil && *sc.Sampled) {
+		value += ";Sampled=1"
+	} else if sc.Sampled != nil {
+		value += ";Sampled=0"
+	}
+
+	return value, nil
+}
+
+// ExtractXRay will extract a span.Context from the carrier if found in X-Amzn-Trace-Id header.
+func ExtractXRay(reader tracing.TextMapReader) propagation.Extractor {
+	return func() (*model.SpanContext, error) {
+		return ParseXRayTraceHeader(reader.Get(XRayTraceHeader))
+	}
+}
+
+// InjectXRay will inject a span.Context into the carrier as X-Amzn-Trace-Id header
+func InjectXRay(writer tracing.TextMapWriter) propagation.Injector {
+	return func(sc model.SpanContext) error {
+		value, err := BuildXRayTraceHeader(sc)
+		if err != nil {
+			return err
+		}
+
+		writer.Set(XRayTraceHeader, value)
+		return nil
+	}
+}
@@ -0,0 +1,153 @@
+package propagation
+
+import (
+	"testing"
+
+	"github.com/Vinelab/tracing-go/carriers"
+	"github.com/openzipkin/zipkin-go/model"
+	"github.com/openzipkin/zipkin-go/propagation/b3"
+)
+
+func TestParseXRayTraceHeader(t *testing.T) {
+	header := "Root=1-5759e988-bd862e3fe1be46a994272793;Parent=53995c3f42cd8ad8;Sampled=1"
+
+	sc, err := ParseXRayTraceHeader(header)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if sc.TraceID.String() != "5759e988bd862e3fe1be46a994272793" {
+		t.Errorf("expected trace 5759e988bd862e3fe1be46a994272793, got %s", sc.TraceID)
+	}
+	if sc.ID.String() != "53995c3f42cd8ad8" {
+		t.Errorf("expected span 53995c3f42cd8ad8, got %s", sc.ID)
+	}
+	if sc.Sampled == nil || !*sc.Sampled {
+		t.Errorf("expected sampled context, got %v", sc.Sampled)
+	}
+}
+
+func TestParseXRayTraceHeaderSampling(t *testing.T) {
+	cases := map[string]*bool{
+		";Sampled=1": &[]bool{true}[0],
+		";Sampled=0": &[]bool{false}[0],
+		";Sampled=?": nil,
+		"":           nil,
+	}
+
+	for sampled, expected := range cases {
+		sc, err := ParseXRayTraceHeader("Root=1-5759e988-bd862e3fe1be46a994272793;Parent=53995c3f42cd8ad8" + sampled)
+		if err != nil {
+			t.Fatalf("%q: %v", sampled, err)
+		}
+
+		switch {
+		case expected == nil && sc.Sampled != nil:
+			t.Errorf("%q: expected the decision left to the sampler, got %v", sampled, *sc.Sampled)
+		case expected != nil && (sc.Sampled == nil || *sc.Sampled != *expected):
+			t.Errorf("%q: expected sampled %v, got %v", sampled, *expected, sc.Sampled)
+		}
+	}
+}
+
+func TestParseXRayTraceHeaderMalformed(t *testing.T) {
+	cases := map[string]error{
+		"Root=1-5759e988-bd862e3fe1be46a994272793":                                     ErrInvalidXRayTraceHeader,
+		"Root=1-5759e988-bd862e3fe1be46a994272793;Self=1-5759e988-bd862e3fe1be46a9942": ErrInvalidXRayTraceHeader,
+		"Parent=53995c3f42cd8ad8;Sampled=1":                                            ErrInvalidXRayTraceHeader,
+		"garbage":                                                                      ErrInvalidXRayTraceHeader,
+		"Root=2-5759e988-bd862e3fe1be46a994272793;Parent=53995c3f42cd8ad8":             ErrInvalidXRayRootValue,
+		"Root=1-5759e98-bd862e3fe1be46a994272793;Parent=53995c3f42cd8ad8":              ErrInvalidXRayRootValue,
+		"Root=1-5759e988-bd862e3fe1be46a99427279;Parent=53995c3f42cd8ad8":              ErrInvalidXRayRootValue,
+		"Root=1-5759e988bd862e3fe1be46a994272793;Parent=53995c3f42cd8ad8":              ErrInvalidXRayRootValue,
+		"Root=1-5759e988-bd862e3fe1be46a99427279z;Parent=53995c3f42cd8ad8":             ErrInvalidXRayRootValue,
+		"Root=1-00000000-000000000000000000000000;Parent=53995c3f42cd8ad8":             ErrInvalidXRayRootValue,
+		"Root=1-5759e988-bd862e3fe1be46a994272793;Parent=53995c3f42cd8ad":              ErrInvalidXRayParentValue,
+		"Root=1-5759e988-bd862e3fe1be46a994272793;Parent=53995c3f42cd8adz":             ErrInvalidXRayParentValue,
+		"Root=1-5759e988-bd862e3fe1be46a994272793;Parent=0000000000000000":             ErrInvalidXRayParentValue,
+	}
+
+	for header, expected := range cases {
+		sc, err := ParseXRayTraceHeader(header)
+		if err != expected || sc != nil {
+			t.Errorf("%s: expected %v, got %v (%v)", header, expected, err, sc)
+		}
+	}
+}
+
+func TestBuildXRayTraceHeader(t *testing.T) {
+	sampled, notSampled := true, false
+	traceID := model.TraceID{High: 0x5759e988bd862e3f, Low: 0xe1be46a994272793}
+
+	cases := []struct {
+		sc       model.SpanContext
+		expected string
+	}{
+		{
+			sc:       model.SpanContext{TraceID: traceID, ID: 0x53995c3f42cd8ad8, Sampled: &sampled},
+			expected: "Root=1-5759e988-bd862e3fe1be46a994272793;Parent=53995c3f42cd8ad8;Sampled=1",
+		},
+		{
+			sc:       model.SpanContext{TraceID: traceID, ID: 0x53995c3f42cd8ad8, Sampled: &notSampled},
+			expected: "Root=1-5759e988-bd862e3fe1be46a994272793;Parent=53995c3f42cd8ad8;Sampled=0",
+		},
+		{
+			sc:       model.SpanContext{TraceID: traceID, ID: 0x53995c3f42cd8ad8, Debug: true},
+			expected: "Root=1-5759e988-bd862e3fe1be46a994272793;Parent=53995c3f42cd8ad8;Sampled=1",
+		},
+		{
+			sc:       model.SpanContext{TraceID: traceID, ID: 0x53995c3f42cd8ad8},
+			expected: "Root=1-5759e988-bd862e3fe1be46a994272793;Parent=53995c3f42cd8ad8",
+		},
+		{
+			sc:       model.SpanContext{TraceID: model.TraceID{Low: 42}, ID: 1},
+			expected: "Root=1-00000000-00000000000000000000002a;Parent=0000000000000001",
+		},
+	}
+
+	for _, c := range cases {
+		header, err := BuildXRayTraceHeader(c.sc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if header != c.expected {
+			t.Errorf("expected %s, got %s", c.expected, header)
+		}
+	}
+}
+
+func TestBuildXRayTraceHeaderInvalid(t *testing.T) {
+	if _, err := BuildXRayTraceHeader(model.SpanContext{}); err != b3.ErrEmptyContext {
+		t.Errorf("expected %v, got %v", b3.ErrEmptyContext, err)
+	}
+	if _, err := BuildXRayTraceHeader(model.SpanContext{ID: 1}); err != ErrInvalidXRayTraceHeader {
+		t.Errorf("expected %v, got %v", ErrInvalidXRayTraceHeader, err)
+	}
+}
+
+func TestXRayRoundTrip(t *testing.T) {
+	header := map[string]string{}
+
+	writer, err := carriers.NewWriter(header)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sc := testSpanContext()
+	if err := InjectXRay(writer)(sc); err != nil {
+		t.Fatal(err)
+	}
+
+	reader, err := carriers.NewReader(header)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	extracted, err := ExtractXRay(reader)()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if extracted.TraceID != sc.TraceID || extracted.ID != sc.ID || extracted.Sampled == nil || !*extracted.Sampled {
+		t.Errorf("expected %v, got %v", sc, extracted)
+	}
+}
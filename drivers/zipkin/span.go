@@ -2,7 +2,12 @@ package zipkin
 
 import (
 	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
+	"unicode/utf8"
 
 	"github.com/Vinelab/tracing-go"
 	"github.com/openzipkin/zipkin-go"
@@ -10,13 +15,20 @@ import (
 
 // Span encapsulates the state of logical operation it represents
 type Span struct {
-	rawSpan zipkin.Span
-	isRoot  bool
+	rawSpan   zipkin.Span
+	isRoot    bool
+	startTime time.Time
+	onFinish  func(span *Span)
+
+	mu             sync.Mutex
+	lastAnnotation time.Time
+	finished       bool
+	baggage        map[string]string
 }
 
 // NewSpan returns a new Span
-func NewSpan(rawSpan zipkin.Span, isRoot bool) *Span {
-	return &Span{rawSpan: rawSpan, isRoot: isRoot}
+func NewSpan(rawSpan zipkin.Span, isRoot bool, startTime time.Time) *Span {
+	return &Span{rawSpan: rawSpan, isRoot: isRoot, startTime: startTime}
 }
 
 // SetName sets (overrides) the string name for the logical operation this span represents.
@@ -41,17 +53,41 @@ func (span *Span) Tag(key string, value string) {
 // timestamp from this, and set when appropriate.
 func (span *Span) Finish() {
 	span.rawSpan.Finish()
+	span.release()
+}
+
+// FinishAt notifies that operation has finished at the given timestamp.
+// Timestamps preceding the start of the span result in zero duration.
+func (span *Span) FinishAt(timestamp time.Time) {
+	duration := timestamp.Sub(span.startTime)
+	if duration < 0 {
+		duration = 0
+	}
+
+	span.rawSpan.FinishedWithDuration(duration)
+	span.release()
+}
+
+// FinishWithDuration notifies that operation has finished after the given duration since the start.
+func (span *Span) FinishWithDuration(duration time.Duration) {
+	span.rawSpan.FinishedWithDuration(duration)
+	span.release()
 }
 
 // Annotate associates an event that explains latency with a timestamp.
 func (span *Span) Annotate(message string) {
-	span.rawSpan.Annotate(time.Now(), message)
+	span.annotate(span.now(), message)
 }
 
-// Log stores structured data. Despite this functionality being outlined in
-// OpenTracing spec it's currently only supported in Jaeger
+// Log stores structured data. Zipkin does not support logs natively, so fields
+// are encoded into a time-stamped annotation as sorted key=value pairs.
 func (span *Span) Log(fields map[string]string) {
-	//
+	span.annotate(span.now(), encodeFields(fields))
+}
+
+// LogAt stores structured data with the given timestamp. Use it for backfilled events
+func (span *Span) LogAt(timestamp time.Time, fields map[string]string) {
+	span.annotate(timestamp, encodeFields(fields))
 }
 
 // IsRoot tells whether the span is a root span
@@ -61,5 +97,90 @@ func (span *Span) IsRoot() bool {
 
 // Context retrieves SpanContext for this Span
 func (span *Span) Context() tracing.SpanContext {
-	return NewSpanContext(span.rawSpan.Context())
+	span.mu.Lock()
+	defer span.mu.Unlock()
+
+	return &SpanContext{rawCtx: span.rawSpan.Context(), baggage: copyBaggage(span.baggage)}
+}
+
+// SetBaggageItem sets the baggage item propagated to child spans and, with formats supporting
+// baggage (i.e. Jaeger), to downstream services.
+func (span *Span) SetBaggageItem(key string, value string) {
+	span.mu.Lock()
+	defer span.mu.Unlock()
+
+	if span.baggage == nil {
+		span.baggage = make(map[string]string)
+	}
+	span.baggage[key] = value
+}
+
+// BaggageItem returns the value of the baggage item or empty string if there is none
+func (span *Span) BaggageItem(key string) string {
+	span.mu.Lock()
+	defer span.mu.Unlock()
+
+	return span.baggage[key]
+}
+
+// release notifies the tracer that the span is no longer active, only once
+func (span *Span) release() {
+	span.mu.Lock()
+	finished := span.finished
+	span.finished = true
+	span.mu.Unlock()
+
+	if !finished && span.onFinish != nil {
+		span.onFinish(span)
+	}
+}
+
+// now returns the current time, but never earlier than the last annotation so that
+// events recorded within the same microsecond keep their order in Zipkin
+func (span *Span) now() time.Time {
+	span.mu.Lock()
+	defer span.mu.Unlock()
+
+	now := time.Now().Truncate(time.Microsecond)
+	if !now.After(span.lastAnnotation) {
+		now = span.lastAnnotation.Add(time.Microsecond)
+	}
+	span.lastAnnotation = now
+
+	return now
+}
+
+func (span *Span) annotate(timestamp time.Time, message string) {
+	if len(message) > MaxAnnotationLen {
+		// Back up to the start of a rune so that multi-byte characters are not split
+		limit := MaxAnnotationLen
+		for limit > 0 && !utf8.RuneStart(message[limit]) {
+			limit--
+		}
+		message = message[:limit] + "..."
+	}
+
+	span.rawSpan.Annotate(timestamp, message)
+}
+
+// encodeFields deterministically encodes fields as key=value pairs sorted by key.
+// Values containing whitespace, quotes or equal signs are quoted.
+func encodeFields(fields map[string]string) string {
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, key := range keys {
+		value := fields[key]
+		if value == "" || strings.ContainsAny(value, " \t\n\"=") {
+			value = strconv.Quote(value)
+		}
+
+		pairs = append(pairs, key+"="+value)
+	}
+
+	return strings.Join(pairs, " ")
 }
@@ -1,8 +1,16 @@
 package zipkin
 
+import (
+	"log"
+
+	"github.com/Vinelab/tracing-go"
+	"github.com/openzipkin/zipkin-go/model"
+)
+
 // SpanContext holds the context of a Span. It should be initialized using NewSpanContext method.
 type SpanContext struct {
-	rawCtx interface{}
+	rawCtx  interface{}
+	baggage map[string]string
 }
 
 // NewSpanContext returns a new SpanContext
@@ -14,3 +22,99 @@ func NewSpanContext(rawCtx interface{}) *SpanContext {
 func (spanCtx *SpanContext) RawContext() interface{} {
 	return spanCtx.rawCtx
 }
+
+// TraceID returns identifier of the trace or empty string if there is none
+func (spanCtx *SpanContext) TraceID() string {
+	zipkinCtx := spanCtx.model()
+	if zipkinCtx.TraceID.Empty() {
+		return ""
+	}
+
+	return zipkinCtx.TraceID.String()
+}
+
+// SpanID returns identifier of the span or empty string if there is none
+func (spanCtx *SpanContext) SpanID() string {
+	zipkinCtx := spanCtx.model()
+	if zipkinCtx.ID == 0 {
+		return ""
+	}
+
+	return zipkinCtx.ID.String()
+}
+
+// ParentSpanID returns identifier of the parent span or empty string if there is none
+func (spanCtx *SpanContext) ParentSpanID() string {
+	zipkinCtx := spanCtx.model()
+	if zipkinCtx.ParentID == nil {
+		return ""
+	}
+
+	return zipkinCtx.ParentID.String()
+}
+
+// Baggage returns a copy of baggage items propagated along with the trace (i.e. Jaeger's uberctx-* headers)
+func (spanCtx *SpanContext) Baggage() map[string]string {
+	return copyBaggage(spanCtx.baggage)
+}
+
+// BaggageItem returns the value of the baggage item or empty string if there is none
+func (spanCtx *SpanContext) BaggageItem(key string) string {
+	return spanCtx.baggage[key]
+}
+
+// IsSampled tells whether the trace is going to be reported
+func (spanCtx *SpanContext) IsSampled() bool {
+	zipkinCtx := spanCtx.model()
+	return zipkinCtx.Debug || (zipkinCtx.Sampled != nil && *zipkinCtx.Sampled)
+}
+
+// IsDebug tells whether the trace is forcibly sampled
+func (spanCtx *SpanContext) IsDebug() bool {
+	return spanCtx.model().Debug
+}
+
+// IsValid tells whether the context carries a well-formed trace that can be continued
+func (spanCtx *SpanContext) IsValid() bool {
+	zipkinCtx := spanCtx.model()
+	return zipkinCtx.Err == nil && !zipkinCtx.TraceID.Empty() && zipkinCtx.ID != 0
+}
+
+// IsEmpty tells whether the context carries nothing at all, as opposed to an invalid context
+// extracted from a carrier with malformed headers
+func (spanCtx *SpanContext) IsEmpty() bool {
+	zipkinCtx := spanCtx.model()
+	return zipkinCtx.Err == nil && zipkinCtx.TraceID.Empty() && zipkinCtx.ID == 0 &&
+		zipkinCtx.Sampled == nil && !zipkinCtx.Debug
+}
+
+// copyBaggage returns a copy of baggage items, nil if there are none
+func copyBaggage(baggage map[string]string) map[string]string {
+	if len(baggage) == 0 {
+		return nil
+	}
+
+	copied := make(map[string]string, len(baggage))
+	for key, value := range baggage {
+		copied[key] = value
+	}
+
+	return copied
+}
+
+func (spanCtx *SpanContext) model() model.SpanContext {
+	zipkinCtx, _ := spanCtx.rawCtx.(model.SpanContext)
+	return zipkinCtx
+}
+
+// zipkinRawContext asserts that the span context was created by Zipkin driver and returns the underlying context
+func zipkinRawContext(spanCtx tracing.SpanContext) model.SpanContext {
+	rawCtx := spanCtx.RawContext()
+
+	zipkinCtx, ok := rawCtx.(model.SpanContext)
+	if !ok {
+		log.Fatalf("Expected %T, got %T", model.SpanContext{}, rawCtx)
+	}
+
+	return zipkinCtx
+}
@@ -0,0 +1,111 @@
+package zipkin
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/Vinelab/tracing-go/formats"
+	"github.com/openzipkin/zipkin-go/model"
+	"github.com/openzipkin/zipkin-go/propagation/b3"
+	"github.com/streadway/amqp"
+)
+
+func TestIsEmpty(t *testing.T) {
+	sampled := false
+
+	cases := []struct {
+		name   string
+		rawCtx interface{}
+		empty  bool
+	}{
+		{"nil", nil, true},
+		{"zero value", model.SpanContext{}, true},
+		{"sampling decision only", model.SpanContext{Sampled: &sampled}, false},
+		{"debug only", model.SpanContext{Debug: true}, false},
+		{"malformed", model.SpanContext{Err: b3.ErrInvalidTraceIDHeader}, false},
+		{"trace", model.SpanContext{TraceID: model.TraceID{Low: 1}, ID: 2}, false},
+	}
+
+	for _, c := range cases {
+		if empty := NewSpanContext(c.rawCtx).IsEmpty(); empty != c.empty {
+			t.Errorf("%s: expected IsEmpty %v, got %v", c.name, c.empty, empty)
+		}
+	}
+}
+
+func TestJaegerBaggagePropagated(t *testing.T) {
+	tracer, _ := newTestTracer(t)
+
+	incoming := http.Header{}
+	incoming.Set("Uber-Trace-Id", "463ac35c9f6413ad:72485a3953bb6124:0:1")
+	incoming.Set("Uberctx-User-Id", "42")
+
+	spanCtx, err := tracer.Extract(incoming, formats.Jaeger)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	span := tracer.StartSpan("Request", spanCtx)
+	child := tracer.StartSpan("Query", span.Context())
+	child.(*Span).SetBaggageItem("tenant", "acme")
+
+	outgoing := http.Header{}
+	if err := tracer.InjectContext(outgoing, formats.Jaeger, child.Context()); err != nil {
+		t.Fatal(err)
+	}
+
+	if outgoing.Get("uberctx-user-id") != "42" || outgoing.Get("uberctx-tenant") != "acme" {
+		t.Errorf("expected baggage to be propagated, got %v", outgoing)
+	}
+
+	if span.(*Span).BaggageItem("tenant") != "" {
+		t.Error("baggage set on the child must not leak to the parent")
+	}
+}
+
+func TestZeroValueExtractorAndInjectorUseB3(t *testing.T) {
+	tracer, _ := newTestTracer(t)
+	tracer.RegisterExtractionFormat("legacy", &HTTPExtractor{})
+	tracer.RegisterInjectionFormat("legacy", &HTTPInjector{})
+
+	span := tracer.StartSpan("Request", tracer.EmptySpanContext())
+	defer span.Finish()
+
+	header := http.Header{}
+	if err := tracer.InjectContext(header, "legacy", span.Context()); err != nil {
+		t.Fatal(err)
+	}
+	if header.Get(b3.TraceID) != span.Context().TraceID() {
+		t.Fatalf("expected B3 headers, got %v", header)
+	}
+
+	spanCtx, err := tracer.Extract(header, "legacy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if spanCtx.SpanID() != span.Context().SpanID() {
+		t.Errorf("expected span %s, got %s", span.Context().SpanID(), spanCtx.SpanID())
+	}
+}
+
+func TestTransportCarriersWorkWithAllHeaderStyles(t *testing.T) {
+	tracer, _ := newTestTracer(t)
+
+	span := tracer.StartSpan("Publish", tracer.EmptySpanContext())
+	defer span.Finish()
+
+	for _, format := range []string{formats.AMQP, formats.B3Single, formats.W3C, formats.Jaeger, formats.XRay} {
+		msg := &amqp.Publishing{}
+		if err := tracer.InjectContext(msg, format, span.Context()); err != nil {
+			t.Fatalf("%s: %v", format, err)
+		}
+
+		spanCtx, err := tracer.Extract(&amqp.Delivery{Headers: msg.Headers}, format)
+		if err != nil {
+			t.Fatalf("%s: %v", format, err)
+		}
+		if spanCtx.SpanID() != span.Context().SpanID() {
+			t.Errorf("%s: expected span %s, got %s", format, span.Context().SpanID(), spanCtx.SpanID())
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package zipkin
+
+import (
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/Vinelab/tracing-go"
+	"github.com/openzipkin/zipkin-go/model"
+	"github.com/openzipkin/zipkin-go/reporter/recorder"
+)
+
+func newTestTracer(t *testing.T) (*Tracer, *recorder.ReporterRecorder) {
+	t.Helper()
+
+	rec := recorder.NewReporter()
+	tracer, err := NewTracer(TracerOptions{ServiceName: "zipkin-test", Reporter: rec})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return tracer, rec
+}
+
+func finishedSpan(t *testing.T, rec *recorder.ReporterRecorder) model.SpanModel {
+	t.Helper()
+
+	spans := rec.Flush()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+
+	return spans[0]
+}
+
+func TestAnnotationTruncatedOnRuneBoundary(t *testing.T) {
+	tracer, rec := newTestTracer(t)
+
+	span := tracer.StartSpan("Annotated", tracer.EmptySpanContext())
+	span.Annotate("a" + strings.Repeat("é", MaxAnnotationLen))
+	span.Finish()
+
+	annotation := finishedSpan(t, rec).Annotations[0].Value
+	if !utf8.ValidString(annotation) {
+		t.Error("annotation was truncated in the middle of a rune")
+	}
+
+	if !strings.HasSuffix(annotation, "...") || len(annotation) > MaxAnnotationLen+3 {
+		t.Errorf("annotation was not truncated, got %d bytes", len(annotation))
+	}
+}
+
+func TestFinishAtBeforeStartClampsDuration(t *testing.T) {
+	tracer, rec := newTestTracer(t)
+
+	start := time.Now()
+	span := tracer.StartSpan("Backfilled", tracer.EmptySpanContext(), tracing.StartTime(start))
+	span.FinishAt(start.Add(-time.Second))
+
+	if duration := finishedSpan(t, rec).Duration; duration != 0 {
+		t.Errorf("expected zero duration, got %s", duration)
+	}
+}
+
+func TestReferenceAttributesAreSanitized(t *testing.T) {
+	tracer, rec := newTestTracer(t)
+
+	linked := NewSpanContext(model.SpanContext{TraceID: model.TraceID{Low: 1}, ID: 2})
+	span := tracer.StartSpan("Batch", tracer.EmptySpanContext(), tracing.Link(linked, map[string]string{
+		"payload": strings.Repeat("x", MaxTagLen+1),
+	}))
+	span.Finish()
+
+	tags := finishedSpan(t, rec).Tags
+	if tags["link.0.span_id"] != "0000000000000002" {
+		t.Errorf("unexpected link.0.span_id %q", tags["link.0.span_id"])
+	}
+
+	if len(tags["link.0.payload"]) > MaxTagLen {
+		t.Errorf("link attribute exceeds MaxTagLen, got %d bytes", len(tags["link.0.payload"]))
+	}
+}
@@ -0,0 +1,142 @@
+package zipkin
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/Vinelab/tracing-go"
+	"github.com/openzipkin/zipkin-go/model"
+	"github.com/openzipkin/zipkin-go/reporter"
+	httpreporter "github.com/openzipkin/zipkin-go/reporter/http"
+)
+
+const (
+	// defaultMaxBacklog is the maximum number of spans waiting to be delivered by the http reporter
+	defaultMaxBacklog = 1000
+)
+
+// reporterStats is implemented by reporters that expose their self-telemetry, see buffered.Reporter
+type reporterStats interface {
+	Stats() tracing.Stats
+}
+
+// httpReporter is Zipkin http reporter that exposes its self-telemetry. Spans that would exceed
+// the backlog are dropped before they reach the wrapped reporter, so that it never disposes spans itself.
+type httpReporter struct {
+	reporter.Reporter
+	*httpReporterStats
+}
+
+func newHTTPReporter(url string, timeout time.Duration, maxBacklog int) *httpReporter {
+	stats := &httpReporterStats{client: &http.Client{}, maxBacklog: maxBacklog}
+
+	return &httpReporter{
+		Reporter: httpreporter.NewReporter(
+			url,
+			httpreporter.Timeout(timeout),
+			httpreporter.MaxBacklog(maxBacklog),
+			httpreporter.Client(stats),
+			httpreporter.Serializer(stats),
+		),
+		httpReporterStats: stats,
+	}
+}
+
+// Send passes the span to the wrapped reporter unless the backlog is full
+func (rep *httpReporter) Send(span model.SpanModel) {
+	if !rep.admit() {
+		return
+	}
+
+	rep.Reporter.Send(span)
+}
+
+// httpReporterStats collects self-telemetry of Zipkin http reporter by serving as its
+// serializer and client. The reporter sends the whole backlog at once, so the size of the last
+// serialized batch is attributed to the following request. The batch that failed to be sent is kept
+// and sent again along with newer spans, so it is only counted once delivered or rejected by the collector.
+type httpReporterStats struct {
+	reporter.JSONSerializer
+	client     *http.Client
+	maxBacklog int
+
+	mu          sync.Mutex
+	backlog     int
+	pending     int
+	reported    uint64
+	dropped     uint64
+	lastError   string
+	lastErrorAt time.Time
+	latency     time.Duration
+}
+
+// admit accounts for the span handed over to the reporter, or drops it when the backlog is full
+func (stats *httpReporterStats) admit() bool {
+	stats.mu.Lock()
+	defer stats.mu.Unlock()
+
+	if stats.backlog >= stats.maxBacklog {
+		stats.dropped++
+		return false
+	}
+
+	stats.backlog++
+	return true
+}
+
+// Serialize remembers the size of the batch before serializing it
+func (stats *httpReporterStats) Serialize(spans []*model.SpanModel) ([]byte, error) {
+	stats.mu.Lock()
+	stats.pending = len(spans)
+	stats.mu.Unlock()
+
+	return stats.JSONSerializer.Serialize(spans)
+}
+
+// Do sends the request and records its outcome
+func (stats *httpReporterStats) Do(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := stats.client.Do(req)
+
+	stats.mu.Lock()
+	defer stats.mu.Unlock()
+
+	stats.latency = time.Since(start)
+	switch {
+	case err != nil:
+		// Zipkin http reporter keeps the batch and retries it upon the next send
+		stats.lastError = err.Error()
+		stats.lastErrorAt = time.Now()
+		stats.pending = 0
+		return resp, err
+	case resp.StatusCode >= 200 && resp.StatusCode <= 299:
+		stats.reported += uint64(stats.pending)
+	default:
+		// Batches the collector responded to are discarded regardless of the status
+		stats.dropped += uint64(stats.pending)
+		stats.lastError = fmt.Sprintf("collector responded with status %d", resp.StatusCode)
+		stats.lastErrorAt = time.Now()
+	}
+
+	stats.backlog -= stats.pending
+	stats.pending = 0
+
+	return resp, err
+}
+
+// Stats retrieves reporter related self-telemetry
+func (stats *httpReporterStats) Stats() tracing.Stats {
+	stats.mu.Lock()
+	defer stats.mu.Unlock()
+
+	return tracing.Stats{
+		SpansReported:     stats.reported,
+		SpansDropped:      stats.dropped,
+		QueueDepth:        stats.backlog,
+		LastExportError:   stats.lastError,
+		LastExportErrorAt: stats.lastErrorAt,
+		ExportLatency:     stats.latency,
+	}
+}
@@ -0,0 +1,102 @@
+package zipkin
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Vinelab/tracing-go"
+	"github.com/openzipkin/zipkin-go/model"
+	"github.com/openzipkin/zipkin-go/reporter/recorder"
+)
+
+func sendSpans(rep *httpReporter, count int) {
+	for i := 1; i <= count; i++ {
+		rep.Send(model.SpanModel{SpanContext: model.SpanContext{TraceID: model.TraceID{Low: uint64(i)}, ID: model.ID(i)}})
+	}
+}
+
+func collectorResponding(t *testing.T, status int) string {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv.URL
+}
+
+func TestHTTPReporterStatsDelivered(t *testing.T) {
+	rep := newHTTPReporter(collectorResponding(t, http.StatusAccepted), time.Second, 10)
+
+	sendSpans(rep, 3)
+	_ = rep.Close()
+
+	if s := rep.Stats(); s.SpansReported != 3 || s.SpansDropped != 0 || s.QueueDepth != 0 || s.LastExportError != "" {
+		t.Errorf("expected 3 reported spans, got %+v", s)
+	}
+}
+
+func TestHTTPReporterStatsRejected(t *testing.T) {
+	rep := newHTTPReporter(collectorResponding(t, http.StatusBadRequest), time.Second, 10)
+
+	sendSpans(rep, 2)
+	_ = rep.Close()
+
+	if s := rep.Stats(); s.SpansReported != 0 || s.SpansDropped != 2 || s.QueueDepth != 0 || s.LastExportError == "" {
+		t.Errorf("expected 2 dropped spans, got %+v", s)
+	}
+}
+
+func TestHTTPReporterStatsBacklog(t *testing.T) {
+	down := httptest.NewServer(http.NotFoundHandler())
+	down.Close()
+
+	rep := newHTTPReporter(down.URL, time.Second, 3)
+
+	// Spans exceeding the backlog are dropped before they reach the reporter
+	sendSpans(rep, 5)
+	_ = rep.Close()
+
+	s := rep.Stats()
+	if s.SpansDropped != 2 || s.QueueDepth != 3 || s.SpansReported != 0 {
+		t.Errorf("expected 3 spans awaiting retry and 2 dropped, got %+v", s)
+	}
+	if s.LastExportError == "" || s.LastExportErrorAt.IsZero() {
+		t.Errorf("expected the transport error recorded, got %+v", s)
+	}
+}
+
+// statsReporter is a custom reporter exposing its self-telemetry
+type statsReporter struct {
+	*recorder.ReporterRecorder
+}
+
+func (rep statsReporter) Stats() tracing.Stats {
+	return tracing.Stats{SpansReported: 42}
+}
+
+func TestCustomReporterStats(t *testing.T) {
+	tracer, err := NewTracer(TracerOptions{ServiceName: "stats-test", Reporter: recorder.NewReporter()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tracer.StartSpan("Request", tracer.EmptySpanContext()).Finish()
+
+	// Spans handed over to the reporter are not known to be delivered
+	if s := tracer.Stats(); s.SpansStarted != 1 || s.SpansFinished != 1 || s.SpansReported != 0 {
+		t.Errorf("expected only started and finished spans counted, got %+v", s)
+	}
+
+	tracer, err = NewTracer(TracerOptions{ServiceName: "stats-test", Reporter: statsReporter{recorder.NewReporter()}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if s := tracer.Stats(); s.SpansReported != 42 {
+		t.Errorf("expected stats of the reporter, got %+v", s)
+	}
+}
@@ -5,20 +5,29 @@ import (
 	"fmt"
 	"log"
 	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/Vinelab/tracing-go"
+	"github.com/Vinelab/tracing-go/drivers/zipkin/buffered"
 	"github.com/Vinelab/tracing-go/formats"
+	"github.com/Vinelab/tracing-go/support/stack"
 	"github.com/google/uuid"
 	openzipkin "github.com/openzipkin/zipkin-go"
+	"github.com/openzipkin/zipkin-go/idgenerator"
 	"github.com/openzipkin/zipkin-go/model"
+	"github.com/openzipkin/zipkin-go/propagation/b3"
 	"github.com/openzipkin/zipkin-go/reporter"
-	httpreporter "github.com/openzipkin/zipkin-go/reporter/http"
 )
 
 const (
 	// MaxTagLen controls the maximum size of tag value in bytes
 	MaxTagLen = 1048576
+	// MaxAnnotationLen controls the maximum size of annotation value (including encoded logs) in bytes
+	MaxAnnotationLen = 4096
 	// DefaultRequestTimeout sets maximum timeout for http request to send spans
 	DefaultRequestTimeout = time.Second * 5
 )
@@ -33,11 +42,16 @@ var (
 type Tracer struct {
 	tracing           *openzipkin.Tracer
 	reporter          reporter.Reporter
+	reporterStats     reporterStats
 	extractionFormats map[string]tracing.Extractor
 	injectionFormats  map[string]tracing.Injector
-	rootSpan          tracing.Span
-	currentSpan       tracing.Span
-	uuid              string
+	activeSpans       stack.Spans
+	spansStarted      uint64
+	spansFinished     uint64
+
+	mu       sync.Mutex
+	rootSpan tracing.Span
+	uuid     string
 }
 
 // TracerOptions is a configuration container to setup the Tracer.
@@ -46,15 +60,19 @@ type TracerOptions struct {
 	// Required
 	ServiceName string
 	// Host
-	// Required
+	// Required unless Reporter is given
 	Host string
 	// Port
-	// Required
+	// Required unless Reporter is given
 	Port string
 	// UsesTraceID128Bit tells whether to use 128 bit trace IDs (32 characters in length as opposed to 16)
 	// Defaults to false
 	UsesTraceID128Bit bool
-	// Reporter option allows to inject your own reporter for tests
+	// IDGenerator overrides generation of trace and span IDs (i.e. idgenerator.NewRandomTimestamped for X-Ray)
+	// Defaults to random IDs, see UsesTraceID128Bit
+	IDGenerator idgenerator.IDGenerator
+	// Reporter option allows to inject your own reporter for tests. Implement Stats() tracing.Stats
+	// method to expose its self-telemetry via Tracer.Stats
 	// Defaults to http reporter
 	Reporter reporter.Reporter
 	// Timeout sets maximum timeout for http request to send spans
@@ -63,15 +81,24 @@ type TracerOptions struct {
 	// Note that reporter will re-try after the first failure.
 	// 	See this issue for more details: https://github.com/openzipkin/zipkin-go/issues/147
 	RequestTimeout time.Duration
+	// Buffered enables the reporter with bounded in-memory queue, batching, exponential
+	// backoff and disk spooling while collector is down. See buffered.Options for details
+	// Defaults to http reporter
+	Buffered *buffered.Options
 }
 
 // NewTracer returns a new Zipkin tracer.
 func NewTracer(opt TracerOptions) (*Tracer, error) {
-	ipAddr, err := resolveCollectorIP(opt.Host)
-	if err != nil {
-		log.Printf("Unable to resolve collector's IP address from hostname %s: %s", opt.Host, err.Error())
+	// Custom reporters (i.e. the one of stdout driver) may not need a collector at all
+	hostPort := ""
+	if opt.Reporter == nil || opt.Host != "" {
+		ipAddr, err := resolveCollectorIP(opt.Host)
+		if err != nil {
+			log.Printf("Unable to resolve collector's IP address from hostname %s: %s", opt.Host, err.Error())
+		}
+		opt.Host = ipAddr
+		hostPort = fmt.Sprintf("%s:%s", opt.Host, opt.Port)
 	}
-	opt.Host = ipAddr
 
 	var timeout time.Duration
 	if opt.RequestTimeout != 0 {
@@ -80,31 +107,51 @@ func NewTracer(opt TracerOptions) (*Tracer, error) {
 		timeout = DefaultRequestTimeout
 	}
 
+	url := fmt.Sprintf("http://%s:%s/api/v2/spans", opt.Host, opt.Port)
+
 	var rep reporter.Reporter
 	if opt.Reporter != nil {
 		rep = opt.Reporter
+	} else if opt.Buffered != nil {
+		bufferedOpt := *opt.Buffered
+		if bufferedOpt.RequestTimeout == 0 {
+			bufferedOpt.RequestTimeout = timeout
+		}
+
+		bufferedRep, err := buffered.NewReporter(url, bufferedOpt)
+		if err != nil {
+			return nil, err
+		}
+		rep = bufferedRep
 	} else {
-		url := fmt.Sprintf("http://%s:%s/api/v2/spans", opt.Host, opt.Port)
-		rep = httpreporter.NewReporter(url, httpreporter.Timeout(timeout))
+		rep = newHTTPReporter(url, timeout, defaultMaxBacklog)
 	}
 
-	endpoint, err := openzipkin.NewEndpoint(opt.ServiceName, fmt.Sprintf("%s:%s", opt.Host, opt.Port))
+	endpoint, err := openzipkin.NewEndpoint(opt.ServiceName, hostPort)
 	if err != nil {
 		return nil, err
 	}
 
-	trace, err := openzipkin.NewTracer(
-		rep,
+	tracerOptions := []openzipkin.TracerOption{
 		openzipkin.WithLocalEndpoint(endpoint),
 		openzipkin.WithTraceID128Bit(opt.UsesTraceID128Bit),
-	)
+	}
+	if opt.IDGenerator != nil {
+		tracerOptions = append(tracerOptions, openzipkin.WithIDGenerator(opt.IDGenerator))
+	}
+
+	trace, err := openzipkin.NewTracer(rep, tracerOptions...)
 	if err != nil {
 		return nil, err
 	}
 
+	// Custom reporters expose their self-telemetry only when they implement Stats method
+	stats, _ := rep.(reporterStats)
+
 	return &Tracer{
 		tracing:           trace,
 		reporter:          rep,
+		reporterStats:     stats,
 		extractionFormats: registerDefaultExtractionFormats(),
 		injectionFormats:  registerDefaultInjectionFormats(),
 	}, nil
@@ -116,22 +163,53 @@ func NewTracer(opt TracerOptions) (*Tracer, error) {
 //
 // If parent context does not contain a trace, a new trace will be implicitly created.
 // Use EmptySpanContext to supply empty (nil) context.
-func (tracer *Tracer) StartSpan(name string, spanCtx tracing.SpanContext) tracing.Span {
-	rawCtx := spanCtx.RawContext()
-	parent, ok := rawCtx.(model.SpanContext)
+//
+// Options such as StartTime may be supplied to customize the span.
+func (tracer *Tracer) StartSpan(name string, spanCtx tracing.SpanContext, opts ...tracing.StartSpanOption) tracing.Span {
+	options := tracing.NewStartSpanOptions(opts...)
+
+	startTime := options.StartTime
+	if startTime.IsZero() {
+		startTime = time.Now()
+	}
+
+	spanOptions := []openzipkin.SpanOption{openzipkin.StartTime(startTime)}
+	if options.Kind != "" {
+		spanOptions = append(spanOptions, openzipkin.Kind(model.Kind(strings.ToUpper(string(options.Kind)))))
+	}
+
+	// Zipkin has no notion of follows-from references, so the first one becomes
+	// the parent unless it was given explicitly. The rest are recorded as tags.
+	references := options.References
+	if !spanCtx.IsValid() {
+		for i, reference := range references {
+			if reference.Type == tracing.FollowsFromReference && reference.SpanContext.IsValid() {
+				spanCtx = reference.SpanContext
+				references = append(references[:i:i], references[i+1:]...)
+				break
+			}
+		}
+	}
 
-	var rawSpan openzipkin.Span
+	parent, ok := spanCtx.RawContext().(model.SpanContext)
+	if options.Sampled != nil && parent.Sampled == nil && !parent.Debug {
+		// Zipkin starts a new trace carrying the decision when parent has no identifiers
+		sampled := *options.Sampled
+		parent.Sampled = &sampled
+		ok = true
+	}
 	if ok {
-		rawSpan = tracer.tracing.StartSpan(name, openzipkin.Parent(parent))
-	} else {
-		rawSpan = tracer.tracing.StartSpan(name)
+		spanOptions = append(spanOptions, openzipkin.Parent(parent))
 	}
 
+	rawSpan := tracer.tracing.StartSpan(name, spanOptions...)
+
+	tracer.mu.Lock()
 	var span *Span
-	if tracer.rootSpan != nil {
-		span = NewSpan(rawSpan, false)
+	if tracer.rootSpan != nil || options.Detached {
+		span = NewSpan(rawSpan, false, startTime)
 	} else {
-		span = NewSpan(rawSpan, true)
+		span = NewSpan(rawSpan, true, startTime)
 		tracer.rootSpan = span
 
 		value, err := uuid.NewUUID()
@@ -141,8 +219,24 @@ func (tracer *Tracer) StartSpan(name string, spanCtx tracing.SpanContext) tracin
 		tracer.uuid = value.String()
 		span.Tag("uuid", tracer.uuid)
 	}
+	tracer.mu.Unlock()
 
-	tracer.currentSpan = span
+	// Baggage is inherited from the parent
+	if parentCtx, ok := spanCtx.(*SpanContext); ok {
+		span.baggage = copyBaggage(parentCtx.baggage)
+	}
+
+	if len(references) < len(options.References) {
+		span.Tag("reference_type", string(tracing.FollowsFromReference))
+	}
+	tagReferences(span, references)
+
+	// Finishing the span restores its parent as the current span
+	if !options.Detached {
+		span.onFinish = tracer.deactivate
+		tracer.activeSpans.Push(span)
+	}
+	atomic.AddUint64(&tracer.spansStarted, 1)
 	span.SetName(name)
 
 	return span
@@ -150,16 +244,22 @@ func (tracer *Tracer) StartSpan(name string, spanCtx tracing.SpanContext) tracin
 
 // RootSpan retrieves the root span of the service
 func (tracer *Tracer) RootSpan() tracing.Span {
+	tracer.mu.Lock()
+	defer tracer.mu.Unlock()
+
 	return tracer.rootSpan
 }
 
-// CurrentSpan retrieves the most recently activated span.
+// CurrentSpan retrieves the innermost span that has not finished yet.
 func (tracer *Tracer) CurrentSpan() tracing.Span {
-	return tracer.currentSpan
+	return tracer.activeSpans.Top()
 }
 
 // UUID retrieves unique identifier associated with a root span
 func (tracer *Tracer) UUID() string {
+	tracer.mu.Lock()
+	defer tracer.mu.Unlock()
+
 	return tracer.uuid
 }
 
@@ -168,6 +268,25 @@ func (tracer *Tracer) EmptySpanContext() tracing.SpanContext {
 	return NewSpanContext(nil)
 }
 
+// BuildSpanContext returns span context of the remote span with given hex encoded identifiers
+func (tracer *Tracer) BuildSpanContext(traceID string, spanID string, sampled bool) (tracing.SpanContext, error) {
+	zipkinTraceID, err := model.TraceIDFromHex(traceID)
+	if err != nil || zipkinTraceID.Empty() {
+		return nil, b3.ErrInvalidTraceIDHeader
+	}
+
+	zipkinSpanID, err := strconv.ParseUint(spanID, 16, 64)
+	if err != nil || len(spanID) > 16 || zipkinSpanID == 0 {
+		return nil, b3.ErrInvalidSpanIDHeader
+	}
+
+	return NewSpanContext(model.SpanContext{
+		TraceID: zipkinTraceID,
+		ID:      model.ID(zipkinSpanID),
+		Sampled: &sampled,
+	}), nil
+}
+
 // Extract deserializes span context from from a given carrier using the format descriptor
 // that tells tracer how to decode it from the carrier parameters
 func (tracer *Tracer) Extract(carrier interface{}, format string) (tracing.SpanContext, error) {
@@ -188,7 +307,7 @@ func (tracer *Tracer) Extract(carrier interface{}, format string) (tracing.SpanC
 // Inject implicitly serializes current span context using the format descriptor that
 // tells how to encode trace info in the carrier parameters
 func (tracer *Tracer) Inject(carrier interface{}, format string) error {
-	span := tracer.currentSpan
+	span := tracer.CurrentSpan()
 	if span == nil {
 		return nil
 	}
@@ -222,12 +341,31 @@ func (tracer *Tracer) RegisterInjectionFormat(format string, injector tracing.In
 	tracer.injectionFormats[format] = injector
 }
 
+// Stats retrieves self-telemetry of the tracer and its reporter
+func (tracer *Tracer) Stats() tracing.Stats {
+	var stats tracing.Stats
+	if tracer.reporterStats != nil {
+		stats = tracer.reporterStats.Stats()
+	}
+	stats.SpansStarted = atomic.LoadUint64(&tracer.spansStarted)
+	stats.SpansFinished = atomic.LoadUint64(&tracer.spansFinished)
+
+	return stats
+}
+
 // Flush may flush any pending spans to the transport and reset the state of the tracer.
 // Make sure this method is always called after the request is finished.
 func (tracer *Tracer) Flush() {
+	tracer.mu.Lock()
+	rootSpan := tracer.rootSpan
 	tracer.rootSpan = nil
-	tracer.currentSpan = nil
 	tracer.uuid = ""
+	tracer.mu.Unlock()
+
+	// Spans of other traces (i.e. concurrent requests) remain active
+	if rootSpan != nil {
+		tracer.activeSpans.ResetTrace(rootSpan.Context().TraceID())
+	}
 }
 
 // Close does a clean shutdown of the reporter, sending any traces that may be buffered in memory.
@@ -240,6 +378,32 @@ func (tracer *Tracer) Close() error {
 	return tracer.reporter.Close()
 }
 
+// tagReferences records references as link.N.* tags because Zipkin does not support links
+func tagReferences(span *Span, references []tracing.Reference) {
+	n := 0
+	for _, reference := range references {
+		if !reference.SpanContext.IsValid() {
+			continue
+		}
+
+		prefix := fmt.Sprintf("link.%d.", n)
+		span.Tag(prefix+"type", string(reference.Type))
+		span.Tag(prefix+"trace_id", reference.SpanContext.TraceID())
+		span.Tag(prefix+"span_id", reference.SpanContext.SpanID())
+		for key, value := range reference.Attributes {
+			span.Tag(prefix+key, value)
+		}
+
+		n++
+	}
+}
+
+// deactivate removes finished span from the stack of active spans
+func (tracer *Tracer) deactivate(span *Span) {
+	tracer.activeSpans.Remove(span)
+	atomic.AddUint64(&tracer.spansFinished, 1)
+}
+
 func resolveCollectorIP(host string) (string, error) {
 	if net.ParseIP(host) != nil {
 		return host, nil
@@ -272,6 +436,15 @@ func registerDefaultExtractionFormats() map[string]tracing.Extractor {
 	extractionFormats[formats.HTTP] = NewHTTPExtractor()
 	extractionFormats[formats.AMQP] = NewAMQPExtractor()
 	extractionFormats[formats.GooglePubSub] = NewGooglePubSubExtractor()
+	extractionFormats[formats.NATS] = NewB3Extractor()
+	extractionFormats[formats.SQS] = NewB3Extractor()
+	extractionFormats[formats.SNS] = NewB3Extractor()
+	extractionFormats[formats.B3] = NewB3Extractor()
+	extractionFormats[formats.B3Single] = NewB3Extractor()
+	extractionFormats[formats.W3C] = NewW3CExtractor()
+	extractionFormats[formats.Jaeger] = NewJaegerExtractor()
+	extractionFormats[formats.XRay] = NewXRayExtractor()
+	extractionFormats[formats.Datadog] = NewDatadogExtractor()
 
 	return extractionFormats
 }
@@ -283,6 +456,15 @@ func registerDefaultInjectionFormats() map[string]tracing.Injector {
 	injectionFormats[formats.HTTP] = NewHTTPInjector()
 	injectionFormats[formats.AMQP] = NewAMQPInjector()
 	injectionFormats[formats.GooglePubSub] = NewGooglePubSubInjector()
+	injectionFormats[formats.NATS] = NewB3Injector()
+	injectionFormats[formats.SQS] = NewMessageAttributesInjector()
+	injectionFormats[formats.SNS] = NewMessageAttributesInjector()
+	injectionFormats[formats.B3] = NewB3Injector()
+	injectionFormats[formats.B3Single] = NewB3SingleInjector()
+	injectionFormats[formats.W3C] = NewW3CInjector()
+	injectionFormats[formats.Jaeger] = NewJaegerInjector()
+	injectionFormats[formats.XRay] = NewXRayInjector()
+	injectionFormats[formats.Datadog] = NewDatadogInjector()
 
 	return injectionFormats
 }
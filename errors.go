@@ -20,3 +20,16 @@ func NewUnregisteredFormatError(err string, format string) *UnregisteredFormatEr
 func (e *UnregisteredFormatError) Error() string {
 	return fmt.Sprintf("%s %s", e.err, e.format)
 }
+
+// PanicError is returned by Group.Wait when a task panicked
+type PanicError struct {
+	// Value is the value the task panicked with
+	Value interface{}
+	// Stack is the stack trace of the panicking goroutine
+	Stack []byte
+}
+
+// Error returns the string representation of the error
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.Value)
+}
@@ -12,4 +12,35 @@ var (
 
 	// GooglePubSub is a format descriptor for propagating trace context via Google Cloud PubSub message
 	GooglePubSub = "google_pubsub"
+
+	// NATS is a format descriptor for propagating trace context via NATS message headers
+	NATS = "nats"
+
+	// SQS is a format descriptor for propagating trace context via AWS SQS message attributes
+	SQS = "sqs"
+
+	// SNS is a format descriptor for propagating trace context via AWS SNS message attributes
+	SNS = "sns"
+
+	// B3 is a format descriptor for propagating trace context via any supported carrier using B3 headers
+	B3 = "b3"
+
+	// B3Single is a format descriptor for propagating trace context via any supported carrier
+	// using single b3 header
+	B3Single = "b3_single"
+
+	// W3C is a format descriptor for propagating trace context via any supported carrier
+	// using W3C Trace Context traceparent header
+	W3C = "w3c"
+
+	// Jaeger is a format descriptor for propagating trace context via any supported carrier
+	// using Jaeger's uber-trace-id header
+	Jaeger = "jaeger"
+
+	// XRay is a format descriptor for propagating trace context via any supported carrier
+	// using AWS X-Ray X-Amzn-Trace-Id header
+	XRay = "xray"
+
+	// Datadog is a format descriptor for propagating trace context via any supported carrier using Datadog headers
+	Datadog = "datadog"
 )
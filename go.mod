@@ -5,9 +5,25 @@ go 1.12
 require (
 	cloud.google.com/go/kms v1.6.0 // indirect
 	cloud.google.com/go/pubsub v1.3.1
+	github.com/alicebob/miniredis/v2 v2.30.5
+	github.com/aws/aws-sdk-go-v2 v1.20.0
+	github.com/aws/aws-sdk-go-v2/service/sns v1.21.0
+	github.com/aws/aws-sdk-go-v2/service/sqs v1.24.0
 	github.com/go-chi/chi v4.1.2+incompatible
 	github.com/google/uuid v1.3.0
 	github.com/jstemmer/go-junit-report v1.0.0 // indirect
+	github.com/nats-io/nats-server/v2 v2.10.5
+	github.com/nats-io/nats.go v1.31.0
+	github.com/opentracing/opentracing-go v1.2.0
 	github.com/openzipkin/zipkin-go v0.4.1
+	github.com/prometheus/client_golang v1.13.0
+	github.com/rabbitmq/amqp091-go v1.5.0
+	github.com/redis/go-redis/v9 v9.7.3
+	github.com/sirupsen/logrus v1.9.3
 	github.com/streadway/amqp v1.0.0
+	github.com/vmihailenco/msgpack/v5 v5.4.1
+	go.opentelemetry.io/otel v1.24.0
+	go.opentelemetry.io/otel/trace v1.24.0
+	go.uber.org/zap v1.27.0
+	golang.org/x/sync v0.1.0
 )
@@ -0,0 +1,148 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"runtime/debug"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// Go runs fn in a new goroutine within a detached span that follows from the active span of ctx
+// (see ActiveSpan). The span is started before the goroutine, so neither the current span being replaced
+// by the next request nor Flush affect it. It is finished once fn returns.
+//
+// fn receives a context carrying the span and values of ctx, but not its cancellation, so the goroutine
+// may outlive the request. Returned error is tagged on the span. Panics are recovered, recorded on the span
+// and logged, so that the process is not brought down before the span is reported.
+func Go(ctx context.Context, tracer Tracer, name string, fn func(ctx context.Context) error, opts ...StartSpanOption) {
+	span := startTask(ctx, tracer, name, true, opts)
+	ctx = detachedContext{ctx}
+
+	go func() {
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				err := recordPanic(span, recovered)
+				log.Printf("Goroutine %s panicked: %s\n%s", name, err.Error(), err.Stack)
+			}
+		}()
+
+		finishTask(span, fn(ContextWithSpan(ctx, span)))
+	}()
+}
+
+// Group runs tasks in goroutines, each within a detached child span of the active span of the context
+// the group was created with. It wraps errgroup.Group: the first error returned by a task cancels
+// the context of the group and is returned by Wait. It should be initialized using NewGroup method.
+type Group struct {
+	tracer Tracer
+	ctx    context.Context
+	group  *errgroup.Group
+}
+
+// GroupOptions is a configuration container to setup the Group.
+type GroupOptions struct {
+	// Limit caps the number of tasks running at once, turning the group into a worker pool.
+	// Go blocks until a worker is available.
+	// Defaults to no limit
+	Limit int
+}
+
+// NewGroup returns a new Group and the context its tasks derive from, which is cancelled
+// once a task fails or Wait returns
+func NewGroup(ctx context.Context, tracer Tracer, opt GroupOptions) (*Group, context.Context) {
+	// Resolve the parent upfront, so that tasks are not affected by the current span being replaced
+	if parent := ActiveSpan(ctx, tracer); parent != nil {
+		ctx = ContextWithSpan(ctx, parent)
+	}
+
+	group, ctx := errgroup.WithContext(ctx)
+	if opt.Limit > 0 {
+		group.SetLimit(opt.Limit)
+	}
+
+	return &Group{tracer: tracer, ctx: ctx, group: group}, ctx
+}
+
+// Go runs fn in a new goroutine within a detached child span. The span starts once the task starts
+// running, so the time spent waiting for a worker is not included. fn receives the context of the group
+// carrying the span. Returned error is tagged on the span, panics are recorded and returned by Wait as PanicError.
+func (group *Group) Go(name string, fn func(ctx context.Context) error, opts ...StartSpanOption) {
+	group.group.Go(func() (err error) {
+		span := startTask(group.ctx, group.tracer, name, false, opts)
+
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				err = recordPanic(span, recovered)
+			}
+		}()
+
+		err = fn(ContextWithSpan(group.ctx, span))
+		finishTask(span, err)
+
+		return err
+	})
+}
+
+// Wait blocks until all tasks finished and returns the first error returned by a task, if any
+func (group *Group) Wait() error {
+	return group.group.Wait()
+}
+
+// startTask starts a detached span of a task, child of or following from the active span of ctx
+func startTask(ctx context.Context, tracer Tracer, name string, followsFrom bool, opts []StartSpanOption) Span {
+	spanCtx := tracer.EmptySpanContext()
+	startOpts := []StartSpanOption{Detached()}
+
+	if parent := ActiveSpan(ctx, tracer); parent != nil {
+		if followsFrom {
+			startOpts = append(startOpts, FollowsFrom(parent.Context(), nil))
+		} else {
+			spanCtx = parent.Context()
+		}
+	}
+
+	return tracer.StartSpan(name, spanCtx, append(startOpts, opts...)...)
+}
+
+func finishTask(span Span, err error) {
+	if err != nil {
+		span.Tag("error", err.Error())
+	}
+
+	span.Finish()
+}
+
+// recordPanic records the panic on the span and finishes it
+func recordPanic(span Span, recovered interface{}) *PanicError {
+	stack := debug.Stack()
+	span.Tag("error", "true")
+	span.Tag("panic", fmt.Sprint(recovered))
+	span.Tag("panic_stack", string(stack))
+	span.Finish()
+
+	return &PanicError{Value: recovered, Stack: stack}
+}
+
+// detachedContext carries values of the parent context, but not its deadline and cancellation
+type detachedContext struct {
+	parent context.Context
+}
+
+func (ctx detachedContext) Deadline() (time.Time, bool) {
+	return time.Time{}, false
+}
+
+func (ctx detachedContext) Done() <-chan struct{} {
+	return nil
+}
+
+func (ctx detachedContext) Err() error {
+	return nil
+}
+
+func (ctx detachedContext) Value(key interface{}) interface{} {
+	return ctx.parent.Value(key)
+}
@@ -0,0 +1,143 @@
+package tracing_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Vinelab/tracing-go"
+	"github.com/Vinelab/tracing-go/drivers/zipkin"
+	"github.com/openzipkin/zipkin-go/model"
+	"github.com/openzipkin/zipkin-go/reporter/recorder"
+)
+
+// syncBuffer is a buffer safe for concurrent use by the logger and the test
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.buf.String()
+}
+
+func newTestTracer(t *testing.T) (*zipkin.Tracer, *recorder.ReporterRecorder) {
+	t.Helper()
+
+	rec := recorder.NewReporter()
+	tracer, err := zipkin.NewTracer(zipkin.TracerOptions{ServiceName: "goroutine-test", Reporter: rec})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return tracer, rec
+}
+
+// waitSpan waits for the span of given name to be reported
+func waitSpan(t *testing.T, rec *recorder.ReporterRecorder, name string) model.SpanModel {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		for _, span := range rec.Flush() {
+			if span.Name == name {
+				return span
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	t.Fatalf("span %q not recorded", name)
+	return model.SpanModel{}
+}
+
+func TestGoTagsError(t *testing.T) {
+	tracer, rec := newTestTracer(t)
+
+	parent := tracer.StartSpan("Request", tracer.EmptySpanContext())
+	ctx := tracing.ContextWithSpan(context.Background(), parent)
+
+	tracing.Go(ctx, tracer, "Send Invoice", func(ctx context.Context) error {
+		if tracing.SpanFromContext(ctx) == nil {
+			t.Error("expected the span of the task in the context")
+		}
+		return errors.New("mailer down")
+	})
+
+	span := waitSpan(t, rec, "Send Invoice")
+	if span.Tags["error"] != "mailer down" {
+		t.Errorf("expected error tag, got %q", span.Tags["error"])
+	}
+	if span.TraceID.String() != parent.Context().TraceID() {
+		t.Errorf("expected trace %s, got %s", parent.Context().TraceID(), span.TraceID)
+	}
+	parent.Finish()
+}
+
+func TestGoRecoversPanic(t *testing.T) {
+	tracer, rec := newTestTracer(t)
+
+	output := &syncBuffer{}
+	log.SetOutput(output)
+	defer log.SetOutput(os.Stderr)
+
+	tracing.Go(context.Background(), tracer, "Send Invoice", func(ctx context.Context) error {
+		panic("boom")
+	})
+
+	span := waitSpan(t, rec, "Send Invoice")
+	if span.Tags["panic"] != "boom" || span.Tags["error"] != "true" {
+		t.Errorf("expected panic tags, got %v", span.Tags)
+	}
+	if span.Tags["panic_stack"] == "" {
+		t.Error("expected panic stack tag")
+	}
+
+	// The panic is logged right after the span is finished
+	deadline := time.Now().Add(5 * time.Second)
+	for !strings.Contains(output.String(), "Goroutine Send Invoice panicked: panic: boom") {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected the panic to be logged, got %q", output.String())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestGroupReturnsPanicError(t *testing.T) {
+	tracer, rec := newTestTracer(t)
+
+	group, _ := tracing.NewGroup(context.Background(), tracer, tracing.GroupOptions{})
+	group.Go("Resize Image", func(ctx context.Context) error {
+		panic("boom")
+	})
+
+	err := group.Wait()
+	panicErr, ok := err.(*tracing.PanicError)
+	if !ok {
+		t.Fatalf("expected *tracing.PanicError, got %T", err)
+	}
+	if panicErr.Value != "boom" || len(panicErr.Stack) == 0 {
+		t.Errorf("expected panic value and stack, got %v", panicErr)
+	}
+
+	span := waitSpan(t, rec, "Resize Image")
+	if span.Tags["panic"] != "boom" {
+		t.Errorf("expected panic tag, got %v", span.Tags)
+	}
+}
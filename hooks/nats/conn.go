@@ -0,0 +1,163 @@
+package nats
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"runtime/debug"
+	"strings"
+
+	"github.com/Vinelab/tracing-go"
+	_ "github.com/Vinelab/tracing-go/carriers/nats" // *nats.Msg carrier
+	"github.com/Vinelab/tracing-go/formats"
+	gonats "github.com/nats-io/nats.go"
+)
+
+// MsgHandler processes a message received from NATS. The context carries the span of the message,
+// returned error is tagged on the span.
+type MsgHandler func(ctx context.Context, msg *gonats.Msg) error
+
+// Conn traces messages published, requested and received over NATS connection, propagating
+// the trace context via message headers (requires NATS server 2.2 or later).
+// It should be initialized using NewConn method.
+type Conn struct {
+	conn   *gonats.Conn
+	tracer tracing.Tracer
+	format string
+}
+
+// ConnOptions is a configuration container to setup the Conn.
+type ConnOptions struct {
+	// Format is the format descriptor trace context is propagated with
+	// Defaults to formats.NATS
+	Format string
+}
+
+// NewConn returns a new Conn wrapping given NATS connection
+func NewConn(conn *gonats.Conn, tracer tracing.Tracer, opt ConnOptions) *Conn {
+	if opt.Format == "" {
+		opt.Format = formats.NATS
+	}
+
+	return &Conn{conn: conn, tracer: tracer, format: opt.Format}
+}
+
+// PublishMsg publishes the message within a PRODUCER span, child of the active span of ctx
+func (conn *Conn) PublishMsg(ctx context.Context, msg *gonats.Msg) error {
+	span := conn.startSpan(ctx, "NATS Publish", tracing.SpanKindProducer, msg)
+
+	err := conn.tracer.InjectContext(msg, conn.format, span.Context())
+	if err == nil {
+		err = conn.conn.PublishMsg(msg)
+	}
+
+	finishSpan(span, err)
+	return err
+}
+
+// RequestMsg sends the request and waits for the response within a CLIENT span, child of the active span of ctx.
+// Use the deadline of ctx to limit the waiting.
+func (conn *Conn) RequestMsg(ctx context.Context, msg *gonats.Msg) (*gonats.Msg, error) {
+	span := conn.startSpan(ctx, "NATS Request", tracing.SpanKindClient, msg)
+
+	var resp *gonats.Msg
+	err := conn.tracer.InjectContext(msg, conn.format, span.Context())
+	if err == nil {
+		resp, err = conn.conn.RequestMsgWithContext(ctx, msg)
+	}
+
+	finishSpan(span, err)
+	return resp, err
+}
+
+// Subscribe handles messages of the subject, see Handler
+func (conn *Conn) Subscribe(subject string, handler MsgHandler) (*gonats.Subscription, error) {
+	return conn.conn.Subscribe(subject, conn.Handler(handler))
+}
+
+// QueueSubscribe handles messages of the subject as a member of the queue group, see Handler
+func (conn *Conn) QueueSubscribe(subject string, queue string, handler MsgHandler) (*gonats.Subscription, error) {
+	return conn.conn.QueueSubscribe(subject, queue, conn.Handler(handler))
+}
+
+// Handler wraps handling of every message into a span continuing the trace propagated
+// via message headers. Requests (messages expecting a reply) are handled within a SERVER span,
+// other messages within a CONSUMER span. Use it with JetStream subscriptions:
+//
+//	js.Subscribe("orders.*", conn.Handler(handle), gonats.Durable("orders"))
+//
+// The span is detached from the tracer, so that concurrent messages neither nest into each other
+// nor replace the root span of the tracer, handlers should retrieve it from the context.
+// Panics of the handler are recovered, recorded on the span and logged.
+func (conn *Conn) Handler(handler MsgHandler) gonats.MsgHandler {
+	return func(msg *gonats.Msg) {
+		spanCtx, err := conn.tracer.Extract(msg, conn.format)
+		if err != nil || spanCtx == nil {
+			spanCtx = conn.tracer.EmptySpanContext()
+		}
+
+		name, kind := "NATS Receive", tracing.SpanKindConsumer
+		if msg.Reply != "" && !strings.HasPrefix(msg.Reply, "$JS.ACK.") {
+			name, kind = "NATS Respond", tracing.SpanKindServer
+		}
+
+		span := conn.tracer.StartSpan(name, spanCtx, tracing.Kind(kind), tracing.Detached())
+		tagMsg(span, msg)
+
+		defer func() {
+			// The panic would crash the process from the goroutine of nats.go, so it is
+			// recorded on the span and logged the same way tracing.Go does
+			if recovered := recover(); recovered != nil {
+				err := recordPanic(span, recovered)
+				log.Printf("Goroutine %s panicked: %s\n%s", name, err.Error(), err.Stack)
+			}
+		}()
+
+		err = handler(tracing.ContextWithSpan(context.Background(), span), msg)
+
+		finishSpan(span, err)
+	}
+}
+
+func (conn *Conn) startSpan(ctx context.Context, name string, kind tracing.SpanKind, msg *gonats.Msg) tracing.Span {
+	spanCtx := conn.tracer.EmptySpanContext()
+	if parent := tracing.ActiveSpan(ctx, conn.tracer); parent != nil {
+		spanCtx = parent.Context()
+	}
+
+	// Messages published by background code without an active span must not take over the root span of the tracer
+	span := conn.tracer.StartSpan(name, spanCtx, tracing.Kind(kind), tracing.Detached())
+	tagMsg(span, msg)
+
+	return span
+}
+
+func tagMsg(span tracing.Span, msg *gonats.Msg) {
+	span.Tag("type", "nats")
+	span.Tag("nats_subject", msg.Subject)
+	if msg.Reply != "" {
+		span.Tag("nats_reply", msg.Reply)
+	}
+	if msg.Sub != nil && msg.Sub.Queue != "" {
+		span.Tag("nats_queue", msg.Sub.Queue)
+	}
+}
+
+// recordPanic records the panic on the span and finishes it
+func recordPanic(span tracing.Span, recovered interface{}) *tracing.PanicError {
+	stack := debug.Stack()
+	span.Tag("error", "true")
+	span.Tag("panic", fmt.Sprint(recovered))
+	span.Tag("panic_stack", string(stack))
+	span.Finish()
+
+	return &tracing.PanicError{Value: recovered, Stack: stack}
+}
+
+func finishSpan(span tracing.Span, err error) {
+	if err != nil {
+		span.Tag("error", err.Error())
+	}
+
+	span.Finish()
+}
@@ -0,0 +1,244 @@
+package nats
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Vinelab/tracing-go"
+	"github.com/Vinelab/tracing-go/drivers/zipkin"
+	"github.com/nats-io/nats-server/v2/server"
+	gonats "github.com/nats-io/nats.go"
+	"github.com/openzipkin/zipkin-go/model"
+	"github.com/openzipkin/zipkin-go/reporter/recorder"
+)
+
+func setup(t *testing.T) (*Conn, *zipkin.Tracer, *recorder.ReporterRecorder) {
+	t.Helper()
+
+	srv, err := server.NewServer(&server.Options{Host: "127.0.0.1", Port: server.RANDOM_PORT, NoLog: true, NoSigs: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	go srv.Start()
+	t.Cleanup(srv.Shutdown)
+	if !srv.ReadyForConnections(5 * time.Second) {
+		t.Fatal("nats server not ready")
+	}
+
+	nc, err := gonats.Connect(srv.ClientURL())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(nc.Close)
+
+	rec := recorder.NewReporter()
+	tracer, err := zipkin.NewTracer(zipkin.TracerOptions{ServiceName: "nats-test", Reporter: rec})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return NewConn(nc, tracer, ConnOptions{}), tracer, rec
+}
+
+// waitSpans collects recorded spans until the expected number of them is reported
+func waitSpans(t *testing.T, rec *recorder.ReporterRecorder, count int) []model.SpanModel {
+	t.Helper()
+
+	var spans []model.SpanModel
+	deadline := time.Now().Add(5 * time.Second)
+	for len(spans) < count {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected %d spans, got %d", count, len(spans))
+		}
+		spans = append(spans, rec.Flush()...)
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	return spans
+}
+
+func findSpan(t *testing.T, spans []model.SpanModel, name string) model.SpanModel {
+	t.Helper()
+
+	for _, span := range spans {
+		if span.Name == name {
+			return span
+		}
+	}
+
+	t.Fatalf("span %q not recorded", name)
+	return model.SpanModel{}
+}
+
+func TestPublishReceive(t *testing.T) {
+	conn, tracer, rec := setup(t)
+
+	received := make(chan tracing.Span, 1)
+	_, err := conn.Subscribe("orders.created", func(ctx context.Context, msg *gonats.Msg) error {
+		received <- tracing.ActiveSpan(ctx, tracer)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parent := tracer.StartSpan("Request", tracer.EmptySpanContext())
+	ctx := tracing.ContextWithSpan(context.Background(), parent)
+
+	if err := conn.PublishMsg(ctx, gonats.NewMsg("orders.created")); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case span := <-received:
+		if span == nil {
+			t.Fatal("expected the span of the message in the handler context")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("message not received")
+	}
+	parent.Finish()
+
+	spans := waitSpans(t, rec, 3)
+	publish := findSpan(t, spans, "NATS Publish")
+	receive := findSpan(t, spans, "NATS Receive")
+
+	if publish.Kind != model.Producer {
+		t.Errorf("expected PRODUCER publish span, got %q", publish.Kind)
+	}
+	if receive.Kind != model.Consumer {
+		t.Errorf("expected CONSUMER receive span, got %q", receive.Kind)
+	}
+	if receive.TraceID.String() != parent.Context().TraceID() {
+		t.Errorf("expected trace %s, got %s", parent.Context().TraceID(), receive.TraceID)
+	}
+	if receive.ParentID == nil || *receive.ParentID != publish.ID {
+		t.Errorf("expected child of %s, got parent %v", publish.ID, receive.ParentID)
+	}
+	if receive.Tags["nats_subject"] != "orders.created" {
+		t.Errorf("expected subject tag, got %q", receive.Tags["nats_subject"])
+	}
+}
+
+func TestRequestRespond(t *testing.T) {
+	conn, tracer, rec := setup(t)
+
+	_, err := conn.Subscribe("users.get", func(ctx context.Context, msg *gonats.Msg) error {
+		return msg.Respond([]byte("john"))
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parent := tracer.StartSpan("Request", tracer.EmptySpanContext())
+	ctx, cancel := context.WithTimeout(tracing.ContextWithSpan(context.Background(), parent), 5*time.Second)
+	defer cancel()
+
+	resp, err := conn.RequestMsg(ctx, gonats.NewMsg("users.get"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(resp.Data) != "john" {
+		t.Errorf("expected response john, got %q", resp.Data)
+	}
+	parent.Finish()
+
+	spans := waitSpans(t, rec, 3)
+	request := findSpan(t, spans, "NATS Request")
+	respond := findSpan(t, spans, "NATS Respond")
+
+	if request.Kind != model.Client {
+		t.Errorf("expected CLIENT request span, got %q", request.Kind)
+	}
+	if respond.Kind != model.Server {
+		t.Errorf("expected SERVER respond span, got %q", respond.Kind)
+	}
+	// Zipkin shares the span ID between the client and the server side of the request
+	if respond.ID != request.ID || respond.TraceID != request.TraceID {
+		t.Errorf("expected shared span %s, got %s", request.ID, respond.ID)
+	}
+}
+
+func TestHandlerKeepsTracerState(t *testing.T) {
+	conn, tracer, rec := setup(t)
+
+	root := tracer.StartSpan("Worker", tracer.EmptySpanContext())
+	uuid := tracer.UUID()
+
+	handle := conn.Handler(func(ctx context.Context, msg *gonats.Msg) error {
+		if tracer.CurrentSpan() != root {
+			t.Error("expected the message span not to be activated on the tracer")
+		}
+		return errors.New("failed")
+	})
+	handle(gonats.NewMsg("orders.created"))
+	handle(gonats.NewMsg("orders.created"))
+
+	if tracer.RootSpan() != root {
+		t.Error("expected the root span to survive handled messages")
+	}
+	if tracer.UUID() != uuid {
+		t.Errorf("expected uuid %s, got %s", uuid, tracer.UUID())
+	}
+	if tracer.CurrentSpan() != root {
+		t.Error("expected the root span to stay current")
+	}
+
+	spans := waitSpans(t, rec, 2)
+	for _, span := range spans {
+		if span.Tags["error"] != "failed" {
+			t.Errorf("expected error tag, got %q", span.Tags["error"])
+		}
+		if _, ok := span.Tags["uuid"]; ok {
+			t.Error("expected the message span not to become the root span")
+		}
+	}
+	root.Finish()
+}
+
+func TestHandlerPanic(t *testing.T) {
+	conn, _, rec := setup(t)
+
+	output := &bytes.Buffer{}
+	log.SetOutput(output)
+	defer log.SetOutput(os.Stderr)
+
+	handle := conn.Handler(func(ctx context.Context, msg *gonats.Msg) error {
+		panic("boom")
+	})
+	handle(gonats.NewMsg("orders.created"))
+
+	span := findSpan(t, waitSpans(t, rec, 1), "NATS Receive")
+	if span.Tags["panic"] != "boom" || span.Tags["error"] != "true" {
+		t.Errorf("expected panic tags, got %v", span.Tags)
+	}
+	if span.Tags["panic_stack"] == "" {
+		t.Error("expected panic stack tag")
+	}
+	if !strings.Contains(output.String(), "Goroutine NATS Receive panicked: panic: boom") {
+		t.Errorf("expected the panic to be logged, got %q", output.String())
+	}
+}
+
+func TestPublishWithoutActiveSpanKeepsRootSpan(t *testing.T) {
+	conn, tracer, rec := setup(t)
+
+	if err := conn.PublishMsg(context.Background(), gonats.NewMsg("orders.created")); err != nil {
+		t.Fatal(err)
+	}
+
+	if tracer.RootSpan() != nil || tracer.CurrentSpan() != nil {
+		t.Error("expected the publish span not to be activated on the tracer")
+	}
+
+	span := findSpan(t, waitSpans(t, rec, 1), "NATS Publish")
+	if _, ok := span.Tags["uuid"]; ok {
+		t.Error("expected the publish span not to become the root span")
+	}
+}
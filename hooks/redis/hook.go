@@ -0,0 +1,174 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/Vinelab/tracing-go"
+	goredis "github.com/redis/go-redis/v9"
+)
+
+const (
+	// DefaultMaxArgsLen controls the default maximum size of tagged command arguments in bytes
+	DefaultMaxArgsLen = 256
+)
+
+// Hook is a go-redis hook that starts a child span for every command and pipeline.
+// It should be initialized using NewHook method.
+type Hook struct {
+	tracer     tracing.Tracer
+	db         int
+	maxArgsLen int
+}
+
+// HookOptions is a configuration container to setup the Hook.
+type HookOptions struct {
+	// DB is the index of the database the client is connected to
+	// Defaults to 0
+	DB int
+	// MaxArgsLen controls the maximum size of tagged command arguments in bytes.
+	// Arguments exceeding the limit are truncated.
+	// Defaults to DefaultMaxArgsLen
+	MaxArgsLen int
+}
+
+// NewHook returns a new Hook. Register it on the client using AddHook method:
+//
+//	client.AddHook(redis.NewHook(Trace, redis.HookOptions{DB: client.Options().DB}))
+func NewHook(tracer tracing.Tracer, opt HookOptions) *Hook {
+	maxArgsLen := opt.MaxArgsLen
+	if maxArgsLen == 0 {
+		maxArgsLen = DefaultMaxArgsLen
+	}
+
+	return &Hook{
+		tracer:     tracer,
+		db:         opt.DB,
+		maxArgsLen: maxArgsLen,
+	}
+}
+
+// DialHook passes dialing through untouched
+func (hook *Hook) DialHook(next goredis.DialHook) goredis.DialHook {
+	return next
+}
+
+// ProcessHook wraps execution of a single command into a span
+func (hook *Hook) ProcessHook(next goredis.ProcessHook) goredis.ProcessHook {
+	return func(ctx context.Context, cmd goredis.Cmder) error {
+		span := hook.startSpan(ctx, "Redis "+strings.ToUpper(cmd.Name()))
+
+		span.Tag("redis_command", cmd.FullName())
+		span.Tag("redis_key_count", strconv.Itoa(countKeys(cmd)))
+		span.Tag("redis_args", hook.formatArgs(cmd.Args()))
+
+		err := next(tracing.ContextWithSpan(ctx, span), cmd)
+
+		hook.finishSpan(span, err, errors.Is(err, goredis.Nil))
+		return err
+	}
+}
+
+// ProcessPipelineHook wraps execution of a pipeline (or transaction) into a single span
+func (hook *Hook) ProcessPipelineHook(next goredis.ProcessPipelineHook) goredis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []goredis.Cmder) error {
+		span := hook.startSpan(ctx, "Redis Pipeline")
+
+		names := make([]string, 0, len(cmds))
+		args := make([]string, 0, len(cmds))
+		keyCount := 0
+		for _, cmd := range cmds {
+			names = append(names, cmd.FullName())
+			args = append(args, hook.formatArgs(cmd.Args()))
+			keyCount += countKeys(cmd)
+		}
+
+		span.Tag("redis_command", strings.Join(names, " "))
+		span.Tag("redis_key_count", strconv.Itoa(keyCount))
+		span.Tag("redis_args", truncate(strings.Join(args, "\n"), hook.maxArgsLen))
+		span.Tag("redis_pipeline_length", strconv.Itoa(len(cmds)))
+
+		err := next(tracing.ContextWithSpan(ctx, span), cmds)
+
+		miss := false
+		for _, cmd := range cmds {
+			if errors.Is(cmd.Err(), goredis.Nil) {
+				miss = true
+				break
+			}
+		}
+
+		hook.finishSpan(span, err, miss)
+		return err
+	}
+}
+
+func (hook *Hook) startSpan(ctx context.Context, name string) tracing.Span {
+	spanCtx := hook.tracer.EmptySpanContext()
+	if parent := tracing.ActiveSpan(ctx, hook.tracer); parent != nil {
+		spanCtx = parent.Context()
+	}
+
+	// Commands of background code without an active span must not take over the root span of the tracer
+	span := hook.tracer.StartSpan(name, spanCtx, tracing.Detached())
+	span.Tag("type", "redis")
+	span.Tag("redis_db", strconv.Itoa(hook.db))
+
+	return span
+}
+
+func (hook *Hook) finishSpan(span tracing.Span, err error, miss bool) {
+	span.Tag("redis_nil", strconv.FormatBool(miss))
+	if err != nil && !errors.Is(err, goredis.Nil) {
+		span.Tag("error", err.Error())
+	}
+
+	span.Finish()
+}
+
+func (hook *Hook) formatArgs(args []interface{}) string {
+	parts := make([]string, 0, len(args))
+	for _, arg := range args {
+		parts = append(parts, fmt.Sprint(arg))
+	}
+
+	return truncate(strings.Join(parts, " "), hook.maxArgsLen)
+}
+
+// truncate cuts the value to at most limit bytes, backing up to a rune boundary
+// so that multi-byte characters are not split
+func truncate(value string, limit int) string {
+	if len(value) <= limit {
+		return value
+	}
+
+	for limit > 0 && !utf8.RuneStart(value[limit]) {
+		limit--
+	}
+
+	return value[:limit] + "..."
+}
+
+// countKeys estimates the number of keys the command operates on
+func countKeys(cmd goredis.Cmder) int {
+	args := cmd.Args()
+	if len(args) < 2 {
+		return 0
+	}
+
+	switch strings.ToLower(cmd.Name()) {
+	case "mget", "del", "unlink", "exists", "touch", "watch", "sinter", "sunion", "sdiff", "pfcount":
+		return len(args) - 1
+	case "mset", "msetnx":
+		return (len(args) - 1) / 2
+	case "ping", "echo", "select", "auth", "hello", "info", "config", "client", "command", "time",
+		"dbsize", "flushdb", "flushall", "scan", "script", "publish", "subscribe", "psubscribe":
+		return 0
+	}
+
+	return 1
+}
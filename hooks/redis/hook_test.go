@@ -0,0 +1,201 @@
+package redis
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/Vinelab/tracing-go"
+	"github.com/Vinelab/tracing-go/drivers/zipkin"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/openzipkin/zipkin-go/model"
+	"github.com/openzipkin/zipkin-go/reporter/recorder"
+	goredis "github.com/redis/go-redis/v9"
+)
+
+func setup(t *testing.T, opt HookOptions) (*goredis.Client, *zipkin.Tracer, *recorder.ReporterRecorder) {
+	t.Helper()
+
+	srv := miniredis.RunT(t)
+
+	rec := recorder.NewReporter()
+	tracer, err := zipkin.NewTracer(zipkin.TracerOptions{ServiceName: "redis-test", Reporter: rec})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := goredis.NewClient(&goredis.Options{Addr: srv.Addr(), DB: opt.DB})
+	t.Cleanup(func() { _ = client.Close() })
+	client.AddHook(NewHook(tracer, opt))
+
+	return client, tracer, rec
+}
+
+func findSpan(t *testing.T, spans []model.SpanModel, name string) model.SpanModel {
+	t.Helper()
+
+	for _, span := range spans {
+		if span.Name == name {
+			return span
+		}
+	}
+
+	t.Fatalf("span %q not recorded", name)
+	return model.SpanModel{}
+}
+
+func TestCommandSpan(t *testing.T) {
+	client, tracer, rec := setup(t, HookOptions{})
+
+	parent := tracer.StartSpan("Request", tracer.EmptySpanContext())
+	ctx := tracing.ContextWithSpan(context.Background(), parent)
+
+	if err := client.Set(ctx, "user:1", "john", 0).Err(); err != nil {
+		t.Fatal(err)
+	}
+	parent.Finish()
+
+	span := findSpan(t, rec.Flush(), "Redis SET")
+
+	if span.ParentID == nil || span.ParentID.String() != parent.Context().SpanID() {
+		t.Errorf("expected child of %s, got parent %v", parent.Context().SpanID(), span.ParentID)
+	}
+
+	expected := map[string]string{
+		"type":            "redis",
+		"redis_command":   "set",
+		"redis_key_count": "1",
+		"redis_args":      "set user:1 john",
+		"redis_db":        "0",
+		"redis_nil":       "false",
+	}
+	for key, value := range expected {
+		if span.Tags[key] != value {
+			t.Errorf("tag %s: expected %q, got %q", key, value, span.Tags[key])
+		}
+	}
+}
+
+func TestBackgroundCommandKeepsRootSpan(t *testing.T) {
+	client, tracer, rec := setup(t, HookOptions{})
+
+	if err := client.Set(context.Background(), "user:1", "john", 0).Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	if tracer.RootSpan() != nil {
+		t.Error("expected the command span not to become the root span")
+	}
+
+	span := findSpan(t, rec.Flush(), "Redis SET")
+	if _, ok := span.Tags["uuid"]; ok {
+		t.Error("expected no uuid tag on the command span")
+	}
+
+	request := tracer.StartSpan("Request", tracer.EmptySpanContext())
+	if tracer.RootSpan() != request {
+		t.Error("expected the next request span to become the root span")
+	}
+	request.Finish()
+}
+
+func TestNilMiss(t *testing.T) {
+	client, _, rec := setup(t, HookOptions{})
+
+	err := client.Get(context.Background(), "missing").Err()
+	if err != goredis.Nil {
+		t.Fatalf("expected redis.Nil, got %v", err)
+	}
+
+	span := findSpan(t, rec.Flush(), "Redis GET")
+	if span.Tags["redis_nil"] != "true" {
+		t.Errorf("expected redis_nil tag to be true, got %q", span.Tags["redis_nil"])
+	}
+	if _, ok := span.Tags["error"]; ok {
+		t.Errorf("miss should not be tagged as error, got %q", span.Tags["error"])
+	}
+}
+
+func TestCommandError(t *testing.T) {
+	client, _, rec := setup(t, HookOptions{})
+
+	if err := client.Set(context.Background(), "list", "value", 0).Err(); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.LPush(context.Background(), "list", "item").Err(); err == nil {
+		t.Fatal("expected WRONGTYPE error")
+	}
+
+	span := findSpan(t, rec.Flush(), "Redis LPUSH")
+	if !strings.Contains(span.Tags["error"], "WRONGTYPE") {
+		t.Errorf("expected error tag, got %q", span.Tags["error"])
+	}
+}
+
+func TestPipelineSpan(t *testing.T) {
+	client, _, rec := setup(t, HookOptions{DB: 2})
+
+	_, err := client.Pipelined(context.Background(), func(pipe goredis.Pipeliner) error {
+		pipe.MSet(context.Background(), "a", "1", "b", "2")
+		pipe.Get(context.Background(), "missing")
+		return nil
+	})
+	if err != goredis.Nil {
+		t.Fatalf("expected redis.Nil, got %v", err)
+	}
+
+	span := findSpan(t, rec.Flush(), "Redis Pipeline")
+
+	expected := map[string]string{
+		"redis_command":         "mset get",
+		"redis_key_count":       "3",
+		"redis_db":              "2",
+		"redis_nil":             "true",
+		"redis_pipeline_length": "2",
+	}
+	for key, value := range expected {
+		if span.Tags[key] != value {
+			t.Errorf("tag %s: expected %q, got %q", key, value, span.Tags[key])
+		}
+	}
+}
+
+func TestArgsTruncation(t *testing.T) {
+	client, _, rec := setup(t, HookOptions{MaxArgsLen: 10})
+
+	if err := client.Set(context.Background(), "key", strings.Repeat("x", 100), 0).Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	span := findSpan(t, rec.Flush(), "Redis SET")
+	if span.Tags["redis_args"] != "set key xx..." {
+		t.Errorf("expected truncated args, got %q", span.Tags["redis_args"])
+	}
+}
+
+func TestTruncateKeepsRunes(t *testing.T) {
+	tests := []struct {
+		value    string
+		limit    int
+		expected string
+	}{
+		{value: "short", limit: 10, expected: "short"},
+		{value: "abcdef", limit: 3, expected: "abc..."},
+		// "é" takes 2 bytes, cutting at 2 would split it
+		{value: "aébc", limit: 2, expected: "a..."},
+		// "世" takes 3 bytes
+		{value: "世界", limit: 4, expected: "世..."},
+		{value: "世界", limit: 2, expected: "..."},
+	}
+
+	for _, test := range tests {
+		actual := truncate(test.value, test.limit)
+		if actual != test.expected {
+			t.Errorf("truncate(%q, %d): expected %q, got %q", test.value, test.limit, test.expected, actual)
+		}
+		if !utf8.ValidString(actual) {
+			t.Errorf("truncate(%q, %d) produced invalid UTF-8", test.value, test.limit)
+		}
+	}
+}
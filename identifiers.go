@@ -0,0 +1,38 @@
+package tracing
+
+import (
+	"context"
+)
+
+// Identifiers holds identifiers of a trace that are safe to expose to clients and logs
+type Identifiers struct {
+	// TraceID is the hex encoded identifier of the trace
+	TraceID string
+	// SpanID is the hex encoded identifier of the span
+	SpanID string
+	// UUID is the unique identifier associated with a root span, see Tracer.UUID
+	UUID string
+	// Sampled tells whether the trace is going to be reported
+	Sampled bool
+}
+
+// SpanIdentifiers retrieves identifiers of the given span
+func SpanIdentifiers(span Span, tracer Tracer) Identifiers {
+	ids := Identifiers{UUID: tracer.UUID()}
+	if span == nil {
+		return ids
+	}
+
+	spanCtx := span.Context()
+	ids.TraceID = spanCtx.TraceID()
+	ids.SpanID = spanCtx.SpanID()
+	ids.Sampled = spanCtx.IsSampled()
+
+	return ids
+}
+
+// IdentifiersFromContext retrieves identifiers of the span that is active in the context (see ActiveSpan).
+// Use it to correlate logs and responses of non-HTTP handlers with traces
+func IdentifiersFromContext(ctx context.Context, tracer Tracer) Identifiers {
+	return SpanIdentifiers(ActiveSpan(ctx, tracer), tracer)
+}
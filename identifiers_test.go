@@ -0,0 +1,44 @@
+package tracing_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Vinelab/tracing-go"
+)
+
+func TestIdentifiersFromContext(t *testing.T) {
+	tracer, _ := newTestTracer(t)
+
+	root := tracer.StartSpan("Request", tracer.EmptySpanContext())
+	child := tracer.StartSpan("Query", root.Context(), tracing.Detached())
+
+	ids := tracing.IdentifiersFromContext(tracing.ContextWithSpan(context.Background(), child), tracer)
+	if ids.TraceID != child.Context().TraceID() || ids.SpanID != child.Context().SpanID() {
+		t.Errorf("expected identifiers of the span in the context, got %+v", ids)
+	}
+	if ids.UUID == "" || ids.UUID != tracer.UUID() {
+		t.Errorf("expected uuid %s, got %q", tracer.UUID(), ids.UUID)
+	}
+	if !ids.Sampled {
+		t.Error("expected sampled span")
+	}
+
+	// Without a span in the context the current span of the tracer is used
+	ids = tracing.IdentifiersFromContext(context.Background(), tracer)
+	if ids.SpanID != root.Context().SpanID() {
+		t.Errorf("expected identifiers of the current span, got %+v", ids)
+	}
+
+	child.Finish()
+	root.Finish()
+}
+
+func TestIdentifiersWithoutSpan(t *testing.T) {
+	tracer, _ := newTestTracer(t)
+
+	ids := tracing.IdentifiersFromContext(context.Background(), tracer)
+	if ids != (tracing.Identifiers{}) {
+		t.Errorf("expected empty identifiers, got %+v", ids)
+	}
+}
@@ -0,0 +1,77 @@
+package logrus
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Vinelab/tracing-go"
+	gologrus "github.com/sirupsen/logrus"
+)
+
+// Hook is a logrus hook that enriches log entries with identifiers of the span carried
+// by the entry context (see Entry.WithContext). Entries logged without a span in the context
+// are left as is. It should be initialized using NewHook method.
+type Hook struct {
+	tracer       tracing.Tracer
+	mirrorErrors bool
+}
+
+// HookOptions is a configuration container to setup the Hook.
+type HookOptions struct {
+	// MirrorErrors logs error-level entries on the span of the context as well
+	// Defaults to false
+	MirrorErrors bool
+}
+
+// NewHook returns a new Hook. Register it on the logger using AddHook method
+func NewHook(tracer tracing.Tracer, opt HookOptions) *Hook {
+	return &Hook{
+		tracer:       tracer,
+		mirrorErrors: opt.MirrorErrors,
+	}
+}
+
+// Levels returns levels the hook fires for
+func (hook *Hook) Levels() []gologrus.Level {
+	return gologrus.AllLevels
+}
+
+// Fire adds trace_id, span_id, uuid and sampled fields to the entry
+func (hook *Hook) Fire(entry *gologrus.Entry) error {
+	ctx := entry.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	// The current span of the tracer may belong to another request, so only the context is trusted
+	span := tracing.SpanFromContext(ctx)
+	if span == nil {
+		return nil
+	}
+
+	if hook.mirrorErrors && entry.Level <= gologrus.ErrorLevel {
+		fields := map[string]string{
+			"level":   entry.Level.String(),
+			"message": entry.Message,
+		}
+
+		for key, value := range entry.Data {
+			fields[key] = fmt.Sprint(value)
+		}
+
+		span.Log(fields)
+	}
+
+	ids := tracing.SpanIdentifiers(span, hook.tracer)
+	if ids.TraceID != "" {
+		entry.Data["trace_id"] = ids.TraceID
+		entry.Data["span_id"] = ids.SpanID
+		entry.Data["sampled"] = ids.Sampled
+	}
+
+	if ids.UUID != "" {
+		entry.Data["uuid"] = ids.UUID
+	}
+
+	return nil
+}
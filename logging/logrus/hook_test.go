@@ -0,0 +1,86 @@
+package logrus
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/Vinelab/tracing-go"
+	"github.com/Vinelab/tracing-go/drivers/zipkin"
+	"github.com/openzipkin/zipkin-go/reporter/recorder"
+	gologrus "github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+)
+
+func setup(t *testing.T, opt HookOptions) (*gologrus.Logger, *test.Hook, *zipkin.Tracer, *recorder.ReporterRecorder) {
+	t.Helper()
+
+	rec := recorder.NewReporter()
+	tracer, err := zipkin.NewTracer(zipkin.TracerOptions{ServiceName: "logrus-test", Reporter: rec})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	logger := gologrus.New()
+	logger.SetOutput(io.Discard)
+	logger.AddHook(NewHook(tracer, opt))
+
+	return logger, test.NewLocal(logger), tracer, rec
+}
+
+func TestHookAddsIdentifiers(t *testing.T) {
+	logger, entries, tracer, _ := setup(t, HookOptions{})
+
+	span := tracer.StartSpan("Request", tracer.EmptySpanContext())
+	logger.WithContext(tracing.ContextWithSpan(context.Background(), span)).Info("order created")
+	span.Finish()
+
+	expected := map[string]interface{}{
+		"trace_id": span.Context().TraceID(),
+		"span_id":  span.Context().SpanID(),
+		"sampled":  true,
+		"uuid":     tracer.UUID(),
+	}
+	for key, value := range expected {
+		if entries.LastEntry().Data[key] != value {
+			t.Errorf("%s: expected %v, got %v", key, value, entries.LastEntry().Data[key])
+		}
+	}
+}
+
+func TestHookIgnoresCurrentSpan(t *testing.T) {
+	logger, entries, tracer, _ := setup(t, HookOptions{MirrorErrors: true})
+
+	span := tracer.StartSpan("Request", tracer.EmptySpanContext())
+	logger.Error("background failure")
+	span.Finish()
+
+	for _, key := range []string{"trace_id", "span_id", "sampled", "uuid"} {
+		if value, ok := entries.LastEntry().Data[key]; ok {
+			t.Errorf("expected no %s without span in the context, got %v", key, value)
+		}
+	}
+}
+
+func TestHookMirrorsErrors(t *testing.T) {
+	logger, _, tracer, rec := setup(t, HookOptions{MirrorErrors: true})
+
+	span := tracer.StartSpan("Request", tracer.EmptySpanContext())
+	ctx := tracing.ContextWithSpan(context.Background(), span)
+	logger.WithContext(ctx).Info("order created")
+	logger.WithContext(ctx).WithField("order", 42).Error("payment failed")
+	span.Finish()
+
+	spans := rec.Flush()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+	if len(spans[0].Annotations) != 1 {
+		t.Fatalf("expected 1 annotation, got %v", spans[0].Annotations)
+	}
+
+	expected := `level=error message="payment failed" order=42`
+	if spans[0].Annotations[0].Value != expected {
+		t.Errorf("expected %s, got %s", expected, spans[0].Annotations[0].Value)
+	}
+}
@@ -0,0 +1,102 @@
+//go:build go1.21
+
+package slog
+
+import (
+	"context"
+	goslog "log/slog"
+
+	"github.com/Vinelab/tracing-go"
+)
+
+// Handler is a slog.Handler wrapper that enriches log records with identifiers of the
+// span carried by the record context. Records logged without a span in the context are passed
+// through as is. It should be initialized using NewHandler method.
+type Handler struct {
+	next         goslog.Handler
+	tracer       tracing.Tracer
+	mirrorErrors bool
+}
+
+// HandlerOptions is a configuration container to setup the Handler.
+type HandlerOptions struct {
+	// MirrorErrors logs error-level records on the span of the context as well
+	// Defaults to false
+	MirrorErrors bool
+}
+
+// NewHandler returns a new Handler that passes enriched records to the next handler
+func NewHandler(next goslog.Handler, tracer tracing.Tracer, opt HandlerOptions) *Handler {
+	return &Handler{
+		next:         next,
+		tracer:       tracer,
+		mirrorErrors: opt.MirrorErrors,
+	}
+}
+
+// Enabled reports whether the next handler handles records at the given level
+func (handler *Handler) Enabled(ctx context.Context, level goslog.Level) bool {
+	return handler.next.Enabled(ctx, level)
+}
+
+// Handle adds trace_id, span_id, uuid and sampled attributes to the record
+// and passes it to the next handler
+func (handler *Handler) Handle(ctx context.Context, record goslog.Record) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	// The current span of the tracer may belong to another request, so only the context is trusted
+	span := tracing.SpanFromContext(ctx)
+	if span == nil {
+		return handler.next.Handle(ctx, record)
+	}
+
+	if handler.mirrorErrors && record.Level >= goslog.LevelError {
+		fields := map[string]string{
+			"level":   record.Level.String(),
+			"message": record.Message,
+		}
+
+		record.Attrs(func(attr goslog.Attr) bool {
+			fields[attr.Key] = attr.Value.String()
+			return true
+		})
+
+		span.Log(fields)
+	}
+
+	ids := tracing.SpanIdentifiers(span, handler.tracer)
+
+	if ids.TraceID != "" {
+		record.AddAttrs(
+			goslog.String("trace_id", ids.TraceID),
+			goslog.String("span_id", ids.SpanID),
+			goslog.Bool("sampled", ids.Sampled),
+		)
+	}
+
+	if ids.UUID != "" {
+		record.AddAttrs(goslog.String("uuid", ids.UUID))
+	}
+
+	return handler.next.Handle(ctx, record)
+}
+
+// WithAttrs returns a new Handler whose next handler has given attributes
+func (handler *Handler) WithAttrs(attrs []goslog.Attr) goslog.Handler {
+	return &Handler{
+		next:         handler.next.WithAttrs(attrs),
+		tracer:       handler.tracer,
+		mirrorErrors: handler.mirrorErrors,
+	}
+}
+
+// WithGroup returns a new Handler whose next handler has given group
+func (handler *Handler) WithGroup(name string) goslog.Handler {
+	return &Handler{
+		next:         handler.next.WithGroup(name),
+		tracer:       handler.tracer,
+		mirrorErrors: handler.mirrorErrors,
+	}
+}
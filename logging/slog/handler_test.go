@@ -0,0 +1,100 @@
+//go:build go1.21
+
+package slog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	goslog "log/slog"
+	"testing"
+
+	"github.com/Vinelab/tracing-go"
+	"github.com/Vinelab/tracing-go/drivers/zipkin"
+	"github.com/openzipkin/zipkin-go/reporter/recorder"
+)
+
+func setup(t *testing.T, opt HandlerOptions) (*goslog.Logger, *bytes.Buffer, *zipkin.Tracer, *recorder.ReporterRecorder) {
+	t.Helper()
+
+	rec := recorder.NewReporter()
+	tracer, err := zipkin.NewTracer(zipkin.TracerOptions{ServiceName: "slog-test", Reporter: rec})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	output := &bytes.Buffer{}
+	logger := goslog.New(NewHandler(goslog.NewJSONHandler(output, nil), tracer, opt))
+
+	return logger, output, tracer, rec
+}
+
+func decode(t *testing.T, output *bytes.Buffer) map[string]interface{} {
+	t.Helper()
+
+	record := map[string]interface{}{}
+	if err := json.Unmarshal(output.Bytes(), &record); err != nil {
+		t.Fatal(err)
+	}
+
+	return record
+}
+
+func TestHandlerAddsIdentifiers(t *testing.T) {
+	logger, output, tracer, _ := setup(t, HandlerOptions{})
+
+	span := tracer.StartSpan("Request", tracer.EmptySpanContext())
+	logger.InfoContext(tracing.ContextWithSpan(context.Background(), span), "order created")
+
+	record := decode(t, output)
+	expected := map[string]interface{}{
+		"trace_id": span.Context().TraceID(),
+		"span_id":  span.Context().SpanID(),
+		"sampled":  true,
+		"uuid":     tracer.UUID(),
+	}
+	for key, value := range expected {
+		if record[key] != value {
+			t.Errorf("%s: expected %v, got %v", key, value, record[key])
+		}
+	}
+	span.Finish()
+}
+
+func TestHandlerIgnoresCurrentSpan(t *testing.T) {
+	logger, output, tracer, _ := setup(t, HandlerOptions{MirrorErrors: true})
+
+	span := tracer.StartSpan("Request", tracer.EmptySpanContext())
+	logger.ErrorContext(context.Background(), "background failure")
+
+	record := decode(t, output)
+	for _, key := range []string{"trace_id", "span_id", "sampled", "uuid"} {
+		if value, ok := record[key]; ok {
+			t.Errorf("expected no %s without span in the context, got %v", key, value)
+		}
+	}
+	span.Finish()
+}
+
+func TestHandlerMirrorsErrors(t *testing.T) {
+	logger, _, tracer, rec := setup(t, HandlerOptions{MirrorErrors: true})
+
+	span := tracer.StartSpan("Request", tracer.EmptySpanContext())
+	ctx := tracing.ContextWithSpan(context.Background(), span)
+	logger.InfoContext(ctx, "order created")
+	logger.ErrorContext(ctx, "payment failed", "order", "42")
+	span.Finish()
+
+	spans := rec.Flush()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+	if len(spans[0].Annotations) != 1 {
+		t.Fatalf("expected 1 annotation, got %v", spans[0].Annotations)
+	}
+
+	expected := `level=ERROR message="payment failed" order=42`
+	if spans[0].Annotations[0].Value != expected {
+		t.Errorf("expected %s, got %s", expected, spans[0].Annotations[0].Value)
+	}
+}
@@ -0,0 +1,139 @@
+package zap
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Vinelab/tracing-go"
+	gozap "go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// spanFieldKey is the key of the field carrying the span, encoders skip it
+const spanFieldKey = "tracing_span"
+
+// Fields returns zap fields with identifiers of the span carried by the context, along with a field
+// encoders skip that lets Core mirror errors on the span. Nothing is returned when the context carries no span.
+func Fields(ctx context.Context, tracer tracing.Tracer) []gozap.Field {
+	span := tracing.SpanFromContext(ctx)
+	if span == nil {
+		return nil
+	}
+
+	fields := identifierFields(tracing.SpanIdentifiers(span, tracer))
+	return append(fields, zapcore.Field{Key: spanFieldKey, Type: zapcore.SkipType, Interface: span})
+}
+
+// Core is a zapcore.Core wrapper that logs error-level entries on the span of the entry. The span is
+// taken from fields returned by Fields, either passed to the log call or added to the logger using With.
+// It should be initialized using NewCore method.
+type Core struct {
+	zapcore.Core
+	span         tracing.Span
+	mirrorErrors bool
+}
+
+// CoreOptions is a configuration container to setup the Core.
+type CoreOptions struct {
+	// MirrorErrors logs error-level entries on the span of the entry
+	// Defaults to false
+	MirrorErrors bool
+}
+
+// NewCore returns a new Core that wraps the given one
+func NewCore(core zapcore.Core, opt CoreOptions) *Core {
+	return &Core{
+		Core:         core,
+		mirrorErrors: opt.MirrorErrors,
+	}
+}
+
+// With adds structured context to the wrapped Core, remembering the span of the fields if any
+func (core *Core) With(fields []zapcore.Field) zapcore.Core {
+	span := spanOf(fields)
+	if span == nil {
+		span = core.span
+	}
+
+	return &Core{
+		Core:         core.Core.With(fields),
+		span:         span,
+		mirrorErrors: core.mirrorErrors,
+	}
+}
+
+// Check adds the Core to checked entry if the wrapped Core is enabled for the entry level
+func (core *Core) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if core.Enabled(entry.Level) {
+		return checked.AddCore(entry, core)
+	}
+
+	return checked
+}
+
+// Write logs error-level entries on the span of the entry and writes the entry using the wrapped Core
+func (core *Core) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	span := spanOf(fields)
+	if span == nil {
+		span = core.span
+	}
+
+	if core.mirrorErrors && span != nil && entry.Level >= zapcore.ErrorLevel {
+		span.Log(logFields(entry, fields))
+	}
+
+	return core.Core.Write(entry, fields)
+}
+
+func identifierFields(ids tracing.Identifiers) []gozap.Field {
+	fields := make([]gozap.Field, 0, 5)
+	if ids.TraceID != "" {
+		fields = append(fields,
+			gozap.String("trace_id", ids.TraceID),
+			gozap.String("span_id", ids.SpanID),
+			gozap.Bool("sampled", ids.Sampled),
+		)
+	}
+
+	if ids.UUID != "" {
+		fields = append(fields, gozap.String("uuid", ids.UUID))
+	}
+
+	return fields
+}
+
+// spanOf retrieves the span added to fields using Fields helper
+func spanOf(fields []zapcore.Field) tracing.Span {
+	for i := len(fields) - 1; i >= 0; i-- {
+		if fields[i].Key == spanFieldKey && fields[i].Type == zapcore.SkipType {
+			if span, ok := fields[i].Interface.(tracing.Span); ok {
+				return span
+			}
+		}
+	}
+
+	return nil
+}
+
+// logFields converts the entry to span log fields, identifiers of the span itself are left out
+func logFields(entry zapcore.Entry, fields []zapcore.Field) map[string]string {
+	encoder := zapcore.NewMapObjectEncoder()
+	for _, field := range fields {
+		switch field.Key {
+		case "trace_id", "span_id", "sampled", "uuid":
+		default:
+			field.AddTo(encoder)
+		}
+	}
+
+	result := map[string]string{
+		"level":   entry.Level.String(),
+		"message": entry.Message,
+	}
+
+	for key, value := range encoder.Fields {
+		result[key] = fmt.Sprint(value)
+	}
+
+	return result
+}
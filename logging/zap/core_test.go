@@ -0,0 +1,98 @@
+package zap
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Vinelab/tracing-go"
+	"github.com/Vinelab/tracing-go/drivers/zipkin"
+	"github.com/openzipkin/zipkin-go/reporter/recorder"
+	gozap "go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func setup(t *testing.T, opt CoreOptions) (*gozap.Logger, *observer.ObservedLogs, *zipkin.Tracer, *recorder.ReporterRecorder) {
+	t.Helper()
+
+	rec := recorder.NewReporter()
+	tracer, err := zipkin.NewTracer(zipkin.TracerOptions{ServiceName: "zap-test", Reporter: rec})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	core, logs := observer.New(zapcore.InfoLevel)
+
+	return gozap.New(NewCore(core, opt)), logs, tracer, rec
+}
+
+func TestFieldsAddIdentifiers(t *testing.T) {
+	logger, logs, tracer, _ := setup(t, CoreOptions{})
+
+	span := tracer.StartSpan("Request", tracer.EmptySpanContext())
+	logger.Info("order created", Fields(tracing.ContextWithSpan(context.Background(), span), tracer)...)
+	span.Finish()
+
+	entries := logs.All()
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 entry, got %d", len(entries))
+	}
+
+	fields := entries[0].ContextMap()
+	expected := map[string]interface{}{
+		"trace_id": span.Context().TraceID(),
+		"span_id":  span.Context().SpanID(),
+		"sampled":  true,
+		"uuid":     tracer.UUID(),
+	}
+	for key, value := range expected {
+		if fields[key] != value {
+			t.Errorf("%s: expected %v, got %v", key, value, fields[key])
+		}
+	}
+	if _, ok := fields[spanFieldKey]; ok {
+		t.Error("expected the span field to be skipped")
+	}
+}
+
+func TestFieldsIgnoreCurrentSpan(t *testing.T) {
+	_, _, tracer, _ := setup(t, CoreOptions{})
+
+	span := tracer.StartSpan("Request", tracer.EmptySpanContext())
+	if fields := Fields(context.Background(), tracer); len(fields) != 0 {
+		t.Errorf("expected no fields without span in the context, got %v", fields)
+	}
+	span.Finish()
+}
+
+func TestCoreMirrorsErrors(t *testing.T) {
+	logger, _, tracer, rec := setup(t, CoreOptions{MirrorErrors: true})
+
+	span := tracer.StartSpan("Request", tracer.EmptySpanContext())
+	ctx := tracing.ContextWithSpan(context.Background(), span)
+
+	logger.Info("order created", Fields(ctx, tracer)...)
+	logger.Error("payment failed", append(Fields(ctx, tracer), gozap.String("order", "42"))...)
+	logger.With(Fields(ctx, tracer)...).Error("refund failed", gozap.Error(errors.New("declined")))
+	logger.Error("unrelated failure")
+	span.Finish()
+
+	spans := rec.Flush()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+
+	expected := []string{
+		`level=error message="payment failed" order=42`,
+		`error=declined level=error message="refund failed"`,
+	}
+	if len(spans[0].Annotations) != len(expected) {
+		t.Fatalf("expected %d annotations, got %v", len(expected), spans[0].Annotations)
+	}
+	for i, value := range expected {
+		if spans[0].Annotations[i].Value != value {
+			t.Errorf("expected %s, got %s", value, spans[0].Annotations[i].Value)
+		}
+	}
+}
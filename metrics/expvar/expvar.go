@@ -0,0 +1,16 @@
+package expvar
+
+import (
+	goexpvar "expvar"
+
+	"github.com/Vinelab/tracing-go"
+)
+
+// Publish publishes stats of the tracer as an expvar variable with the given name.
+// Importing this package registers the /debug/vars handler on http.DefaultServeMux.
+// Like expvar.Publish, it panics if the name is already in use
+func Publish(name string, tracer tracing.Tracer) {
+	goexpvar.Publish(name, goexpvar.Func(func() interface{} {
+		return tracer.Stats()
+	}))
+}
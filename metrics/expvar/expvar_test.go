@@ -0,0 +1,22 @@
+package expvar
+
+import (
+	goexpvar "expvar"
+	"strings"
+	"testing"
+
+	"github.com/Vinelab/tracing-go/drivers/noop"
+)
+
+func TestPublish(t *testing.T) {
+	Publish("tracing_test", noop.NewTracer())
+
+	value := goexpvar.Get("tracing_test")
+	if value == nil {
+		t.Fatal("variable was not published")
+	}
+
+	if !strings.Contains(value.String(), `"spans_started":`) {
+		t.Errorf("unexpected value %s", value.String())
+	}
+}
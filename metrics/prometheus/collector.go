@@ -0,0 +1,69 @@
+package prometheus
+
+import (
+	"github.com/Vinelab/tracing-go"
+	goprometheus "github.com/prometheus/client_golang/prometheus"
+)
+
+// Collector exposes stats of the tracer as Prometheus metrics.
+// It should be initialized using NewCollector method.
+type Collector struct {
+	tracer tracing.Tracer
+
+	spansStarted    *goprometheus.Desc
+	spansFinished   *goprometheus.Desc
+	spansReported   *goprometheus.Desc
+	spansDropped    *goprometheus.Desc
+	queueDepth      *goprometheus.Desc
+	exportLatency   *goprometheus.Desc
+	lastExportError *goprometheus.Desc
+}
+
+// NewCollector returns a new Collector. Register it using prometheus.MustRegister:
+//
+//	prometheus.MustRegister(tracingprometheus.NewCollector(Trace, "myapp"))
+func NewCollector(tracer tracing.Tracer, namespace string) *Collector {
+	desc := func(name string, help string) *goprometheus.Desc {
+		return goprometheus.NewDesc(goprometheus.BuildFQName(namespace, "tracing", name), help, nil, nil)
+	}
+
+	return &Collector{
+		tracer:          tracer,
+		spansStarted:    desc("spans_started_total", "Number of spans started by the tracer."),
+		spansFinished:   desc("spans_finished_total", "Number of spans finished."),
+		spansReported:   desc("spans_reported_total", "Number of spans delivered to the backend."),
+		spansDropped:    desc("spans_dropped_total", "Number of spans lost on the way to the backend."),
+		queueDepth:      desc("queue_depth", "Number of finished spans waiting to be reported."),
+		exportLatency:   desc("export_latency_seconds", "Duration of the last attempt to report spans."),
+		lastExportError: desc("last_export_error_timestamp_seconds", "Time of the last failure to report spans."),
+	}
+}
+
+// Describe sends descriptors of all metrics to the channel
+func (collector *Collector) Describe(ch chan<- *goprometheus.Desc) {
+	ch <- collector.spansStarted
+	ch <- collector.spansFinished
+	ch <- collector.spansReported
+	ch <- collector.spansDropped
+	ch <- collector.queueDepth
+	ch <- collector.exportLatency
+	ch <- collector.lastExportError
+}
+
+// Collect retrieves stats of the tracer and sends them to the channel
+func (collector *Collector) Collect(ch chan<- goprometheus.Metric) {
+	stats := collector.tracer.Stats()
+
+	var lastExportError float64
+	if !stats.LastExportErrorAt.IsZero() {
+		lastExportError = float64(stats.LastExportErrorAt.UnixNano()) / 1e9
+	}
+
+	ch <- goprometheus.MustNewConstMetric(collector.spansStarted, goprometheus.CounterValue, float64(stats.SpansStarted))
+	ch <- goprometheus.MustNewConstMetric(collector.spansFinished, goprometheus.CounterValue, float64(stats.SpansFinished))
+	ch <- goprometheus.MustNewConstMetric(collector.spansReported, goprometheus.CounterValue, float64(stats.SpansReported))
+	ch <- goprometheus.MustNewConstMetric(collector.spansDropped, goprometheus.CounterValue, float64(stats.SpansDropped))
+	ch <- goprometheus.MustNewConstMetric(collector.queueDepth, goprometheus.GaugeValue, float64(stats.QueueDepth))
+	ch <- goprometheus.MustNewConstMetric(collector.exportLatency, goprometheus.GaugeValue, stats.ExportLatency.Seconds())
+	ch <- goprometheus.MustNewConstMetric(collector.lastExportError, goprometheus.GaugeValue, lastExportError)
+}
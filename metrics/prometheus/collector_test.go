@@ -0,0 +1,74 @@
+package prometheus
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Vinelab/tracing-go"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+// statsTracer is a tracer reporting fixed stats
+type statsTracer struct {
+	tracing.Tracer
+	stats tracing.Stats
+}
+
+func (tracer statsTracer) Stats() tracing.Stats {
+	return tracer.stats
+}
+
+func TestCollect(t *testing.T) {
+	collector := NewCollector(statsTracer{stats: tracing.Stats{
+		SpansStarted:      10,
+		SpansFinished:     9,
+		SpansReported:     6,
+		SpansDropped:      2,
+		QueueDepth:        1,
+		ExportLatency:     250 * time.Millisecond,
+		LastExportErrorAt: time.Unix(1500000000, 0),
+	}}, "app")
+
+	expected := `
+# HELP app_tracing_export_latency_seconds Duration of the last attempt to report spans.
+# TYPE app_tracing_export_latency_seconds gauge
+app_tracing_export_latency_seconds 0.25
+# HELP app_tracing_last_export_error_timestamp_seconds Time of the last failure to report spans.
+# TYPE app_tracing_last_export_error_timestamp_seconds gauge
+app_tracing_last_export_error_timestamp_seconds 1.5e+09
+# HELP app_tracing_queue_depth Number of finished spans waiting to be reported.
+# TYPE app_tracing_queue_depth gauge
+app_tracing_queue_depth 1
+# HELP app_tracing_spans_dropped_total Number of spans lost on the way to the backend.
+# TYPE app_tracing_spans_dropped_total counter
+app_tracing_spans_dropped_total 2
+# HELP app_tracing_spans_finished_total Number of spans finished.
+# TYPE app_tracing_spans_finished_total counter
+app_tracing_spans_finished_total 9
+# HELP app_tracing_spans_reported_total Number of spans delivered to the backend.
+# TYPE app_tracing_spans_reported_total counter
+app_tracing_spans_reported_total 6
+# HELP app_tracing_spans_started_total Number of spans started by the tracer.
+# TYPE app_tracing_spans_started_total counter
+app_tracing_spans_started_total 10
+`
+
+	if err := testutil.CollectAndCompare(collector, strings.NewReader(expected)); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestCollectWithoutExportError(t *testing.T) {
+	collector := NewCollector(statsTracer{}, "app")
+
+	expected := `
+# HELP app_tracing_last_export_error_timestamp_seconds Time of the last failure to report spans.
+# TYPE app_tracing_last_export_error_timestamp_seconds gauge
+app_tracing_last_export_error_timestamp_seconds 0
+`
+
+	if err := testutil.CollectAndCompare(collector, strings.NewReader(expected), "app_tracing_last_export_error_timestamp_seconds"); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,209 @@
+package middleware
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"strings"
+)
+
+var (
+	// StreamingContentTypes lists content-types of responses that are never captured
+	// because they are streamed to the client for an indefinite amount of time
+	StreamingContentTypes = []string{
+		"text/event-stream",
+		"application/x-ndjson",
+		"application/stream+json",
+		"multipart/x-mixed-replace",
+	}
+)
+
+// bodyCapture keeps up to limit bytes written into it and counts the rest
+type bodyCapture struct {
+	limit  int
+	size   int
+	buffer bytes.Buffer
+}
+
+func newBodyCapture(limit int) *bodyCapture {
+	return &bodyCapture{limit: limit}
+}
+
+// Write records the chunk without ever failing so that it is safe to use as a tee
+func (capture *bodyCapture) Write(p []byte) (int, error) {
+	capture.size += len(p)
+
+	room := capture.limit - capture.buffer.Len()
+	if room > 0 {
+		if len(p) > room {
+			capture.buffer.Write(p[:room])
+		} else {
+			capture.buffer.Write(p)
+		}
+	}
+
+	return len(p), nil
+}
+
+// String returns captured data followed by a truncation marker when it exceeded the limit
+func (capture *bodyCapture) String() string {
+	truncated := capture.size - capture.buffer.Len()
+	if truncated <= 0 {
+		return capture.buffer.String()
+	}
+
+	return fmt.Sprintf("%s... [truncated %d bytes]", capture.buffer.String(), truncated)
+}
+
+// requestBody records the request body as the handler consumes it
+type requestBody struct {
+	io.ReadCloser
+	capture *bodyCapture
+}
+
+// Read reads from the original body and records what has been read
+func (body *requestBody) Read(p []byte) (int, error) {
+	n, err := body.ReadCloser.Read(p)
+	body.capture.Write(p[:n])
+	return n, err
+}
+
+// responseCapture decides whether the response is worth capturing upon the first write,
+// when the handler has already populated response headers. Responses the handler flushes
+// without Content-Length are streamed, so they are ruled out even after capturing started.
+type responseCapture struct {
+	*bodyCapture
+	header         http.Header
+	contentTypes   []string
+	anyContentType bool
+	contentType    string
+	decided        bool
+	skipped        bool
+	streaming      bool
+}
+
+// Write records the chunk unless the response has been ruled out
+func (capture *responseCapture) Write(p []byte) (int, error) {
+	if !capture.decided {
+		capture.decided = true
+
+		// Server sniffs the content-type from the first chunk when the handler did not set one
+		capture.contentType = capture.header.Get("Content-Type")
+		if _, ok := capture.header["Content-Type"]; !ok && len(p) > 0 {
+			capture.contentType = http.DetectContentType(p)
+		}
+
+		capture.skipped = matchContentType(StreamingContentTypes, capture.contentType) ||
+			(!capture.anyContentType && !matchContentType(capture.contentTypes, capture.contentType))
+	}
+
+	if capture.skipped || capture.streaming {
+		return len(p), nil
+	}
+
+	return capture.bodyCapture.Write(p)
+}
+
+// flushed rules out the response when the handler flushes it without Content-Length,
+// meaning that it is sent in chunks for possibly indefinite amount of time
+func (capture *responseCapture) flushed() {
+	if capture.streaming || capture.header.Get("Content-Length") != "" {
+		return
+	}
+
+	capture.streaming = true
+	capture.buffer.Reset()
+}
+
+// captured tells whether anything has been recorded
+func (capture *responseCapture) captured() bool {
+	return capture.decided && !capture.skipped && !capture.streaming
+}
+
+// notifyFlush wraps the response writer to call onFlush whenever the handler flushes the response.
+// Optional interfaces chi's WrapResponseWriter relies on are preserved.
+func notifyFlush(w http.ResponseWriter, onFlush func()) http.ResponseWriter {
+	if _, ok := w.(http.Flusher); !ok {
+		return w
+	}
+
+	fw := flushNotifier{ResponseWriter: w, onFlush: onFlush}
+
+	_, hj := w.(http.Hijacker)
+	_, rf := w.(io.ReaderFrom)
+	if hj && rf {
+		return &http1FlushNotifier{fw}
+	}
+
+	if _, ps := w.(http.Pusher); ps {
+		return &http2FlushNotifier{fw}
+	}
+
+	return &fw
+}
+
+// flushNotifier calls onFlush before flushing the response
+type flushNotifier struct {
+	http.ResponseWriter
+	onFlush func()
+}
+
+// Flush sends buffered data to the client
+func (fw *flushNotifier) Flush() {
+	fw.onFlush()
+	fw.ResponseWriter.(http.Flusher).Flush()
+}
+
+// http1FlushNotifier is flushNotifier that also satisfies http.Hijacker and io.ReaderFrom
+type http1FlushNotifier struct {
+	flushNotifier
+}
+
+// Hijack lets the handler take over the connection
+func (fw *http1FlushNotifier) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return fw.ResponseWriter.(http.Hijacker).Hijack()
+}
+
+// ReadFrom copies the reader into the response
+func (fw *http1FlushNotifier) ReadFrom(r io.Reader) (int64, error) {
+	return fw.ResponseWriter.(io.ReaderFrom).ReadFrom(r)
+}
+
+// http2FlushNotifier is flushNotifier that also satisfies http.Pusher
+type http2FlushNotifier struct {
+	flushNotifier
+}
+
+// Push initiates HTTP/2 server push
+func (fw *http2FlushNotifier) Push(target string, opts *http.PushOptions) error {
+	return fw.ResponseWriter.(http.Pusher).Push(target, opts)
+}
+
+// matchContentType tells whether content-type is whitelisted ignoring parameters such as charset
+func matchContentType(contentTypes []string, contentType string) bool {
+	mediaType := normalizeContentType(contentType)
+	if mediaType == "" {
+		return false
+	}
+
+	for _, candidate := range contentTypes {
+		if normalizeContentType(candidate) == mediaType {
+			return true
+		}
+	}
+
+	return false
+}
+
+func normalizeContentType(contentType string) string {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType = strings.Split(contentType, ";")[0]
+	}
+
+	return strings.ToLower(strings.TrimSpace(mediaType))
+}
@@ -0,0 +1,237 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Vinelab/tracing-go/drivers/zipkin"
+	"github.com/openzipkin/zipkin-go/model"
+	"github.com/openzipkin/zipkin-go/reporter/recorder"
+)
+
+func serve(t *testing.T, opt TraceRequestsOptions, handler http.HandlerFunc) model.SpanModel {
+	t.Helper()
+
+	spans := record(t, opt, nil, handler)
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+
+	return spans[0]
+}
+
+func record(t *testing.T, opt TraceRequestsOptions, header http.Header, handler http.HandlerFunc) []model.SpanModel {
+	t.Helper()
+
+	spans, _, _ := do(t, opt, request{header: header}, handler)
+	return spans
+}
+
+// request describes the request sent to the traced handler, defaults to GET / without body
+type request struct {
+	method string
+	target string
+	header http.Header
+	body   string
+}
+
+// do sends the request through the middleware and returns reported spans along with the response and its body
+func do(t *testing.T, opt TraceRequestsOptions, r request, handler http.Handler) ([]model.SpanModel, *http.Response, string) {
+	t.Helper()
+
+	rec := recorder.NewReporter()
+	tracer, err := zipkin.NewTracer(zipkin.TracerOptions{ServiceName: "middleware-test", Reporter: rec})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	srv := httptest.NewServer(NewTraceRequests(tracer, opt).Handler(handler))
+	defer srv.Close()
+
+	if r.method == "" {
+		r.method = http.MethodGet
+	}
+
+	var body io.Reader
+	if r.body != "" {
+		body = strings.NewReader(r.body)
+	}
+
+	req, err := http.NewRequest(r.method, srv.URL+r.target, body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for key, values := range r.header {
+		req.Header[key] = values
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	content, _ := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+
+	return rec.Flush(), resp, string(content)
+}
+
+func TestSniffedContentTypeCaptured(t *testing.T) {
+	span := serve(t, TraceRequestsOptions{ContentTypes: []string{"text/html"}}, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("<html><body>hello</body></html>"))
+	})
+
+	if span.Tags["response_content"] != "<html><body>hello</body></html>" {
+		t.Errorf("unexpected response_content %q", span.Tags["response_content"])
+	}
+}
+
+func TestFlushedResponseSkipped(t *testing.T) {
+	span := serve(t, TraceRequestsOptions{ContentTypes: []string{"text/plain"}}, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		_, _ = w.Write([]byte("first chunk"))
+		w.(http.Flusher).Flush()
+		_, _ = w.Write([]byte("second chunk"))
+	})
+
+	if content, ok := span.Tags["response_content"]; ok {
+		t.Errorf("streamed response must not be captured, got %q", content)
+	}
+}
+
+func TestFlushedResponseWithContentLengthCaptured(t *testing.T) {
+	span := serve(t, TraceRequestsOptions{ContentTypes: []string{"text/plain"}}, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		w.Header().Set("Content-Length", "5")
+		w.(http.Flusher).Flush()
+		_, _ = w.Write([]byte("hello"))
+	})
+
+	if span.Tags["response_content"] != "hello" {
+		t.Errorf("unexpected response_content %q", span.Tags["response_content"])
+	}
+}
+
+func TestNotifyFlushKeepsInterfaces(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		flushed := false
+		wrapped := notifyFlush(w, func() { flushed = true })
+
+		if _, ok := wrapped.(http.Hijacker); !ok {
+			t.Error("wrapped writer lost http.Hijacker")
+		}
+		if _, ok := wrapped.(io.ReaderFrom); !ok {
+			t.Error("wrapped writer lost io.ReaderFrom")
+		}
+
+		wrapped.(http.Flusher).Flush()
+		if !flushed {
+			t.Error("flush was not notified")
+		}
+	}))
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = resp.Body.Close()
+}
+
+func TestTruncatedBodiesMarked(t *testing.T) {
+	opt := TraceRequestsOptions{ContentTypes: []string{"text/plain"}, MaxRequestBodySize: 4, MaxResponseBodySize: 5}
+	header := http.Header{"Content-Type": {"text/plain"}}
+
+	spans, _, _ := do(t, opt, request{method: http.MethodPost, header: header, body: "ping pong"}, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "text/plain")
+		_, _ = w.Write([]byte("hello world"))
+	}))
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+
+	if spans[0].Tags["request_input"] != "ping... [truncated 5 bytes]" {
+		t.Errorf("unexpected request_input %q", spans[0].Tags["request_input"])
+	}
+	if spans[0].Tags["response_content"] != "hello... [truncated 6 bytes]" {
+		t.Errorf("unexpected response_content %q", spans[0].Tags["response_content"])
+	}
+}
+
+func TestRequestBodyCapturedAsRead(t *testing.T) {
+	cases := map[string]struct {
+		read     int
+		expected string
+	}{
+		"unread":  {read: 0, expected: ""},
+		"partial": {read: 4, expected: "ping"},
+		"full":    {read: -1, expected: "ping pong"},
+	}
+
+	opt := TraceRequestsOptions{ContentTypes: []string{"text/plain"}}
+	header := http.Header{"Content-Type": {"text/plain"}}
+
+	for name, c := range cases {
+		spans, _, _ := do(t, opt, request{method: http.MethodPost, header: header, body: "ping pong"}, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch {
+			case c.read < 0:
+				_, _ = io.ReadAll(r.Body)
+			case c.read > 0:
+				_, _ = io.ReadFull(r.Body, make([]byte, c.read))
+			}
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		if len(spans) != 1 {
+			t.Fatalf("%s: expected 1 span, got %d", name, len(spans))
+		}
+
+		if spans[0].Tags["request_input"] != c.expected {
+			t.Errorf("%s: expected request_input %q, got %q", name, c.expected, spans[0].Tags["request_input"])
+		}
+	}
+}
+
+func TestContentTypeParametersIgnored(t *testing.T) {
+	opt := TraceRequestsOptions{ContentTypes: []string{"application/json"}}
+	header := http.Header{"Content-Type": {"application/json; charset=utf-8"}}
+
+	spans, _, _ := do(t, opt, request{method: http.MethodPost, header: header, body: `{"id":1}`}, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "Application/JSON;charset=UTF-8")
+		_, _ = w.Write([]byte(`{"ok":true}`))
+	}))
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+
+	if spans[0].Tags["request_input"] != `{"id":1}` {
+		t.Errorf("unexpected request_input %q", spans[0].Tags["request_input"])
+	}
+	if spans[0].Tags["response_content"] != `{"ok":true}` {
+		t.Errorf("unexpected response_content %q", spans[0].Tags["response_content"])
+	}
+}
+
+func TestMatchContentType(t *testing.T) {
+	cases := []struct {
+		contentType string
+		expected    bool
+	}{
+		{"application/json", true},
+		{"application/json; charset=utf-8", true},
+		{"APPLICATION/JSON", true},
+		{"text/plain; charset=utf-8", true},
+		{"text/html", false},
+		{"", false},
+		{"not a content type;;", false},
+	}
+
+	for _, c := range cases {
+		if got := matchContentType([]string{"application/json", "text/plain"}, c.contentType); got != c.expected {
+			t.Errorf("%q: expected %v, got %v", c.contentType, c.expected, got)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package middleware
+
+import (
+	"net/http"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// BodyCapture overrides whether request and response bodies are captured for a route
+type BodyCapture int
+
+const (
+	// BodyCaptureDefault captures bodies of whitelisted content-types
+	BodyCaptureDefault BodyCapture = iota
+	// BodyCaptureEnabled captures bodies regardless of their content-types
+	BodyCaptureEnabled
+	// BodyCaptureDisabled never captures bodies
+	BodyCaptureDisabled
+)
+
+// RouteOptions overrides middleware options for requests matching the route.
+// A route matches when url path matches any of Paths, Prefixes or Patterns
+// and the request method is one of Methods.
+type RouteOptions struct {
+	// Paths lists url paths which may contain glob patterns as understood by path.Match, e.g. "/users/*"
+	Paths []string
+	// Prefixes lists url path prefixes, e.g. "/api/v2/"
+	Prefixes []string
+	// Patterns lists regular expressions matched against url path
+	Patterns []*regexp.Regexp
+	// Methods restricts the route to given http methods
+	// Defaults to all methods
+	Methods []string
+	// BodyCapture overrides whether request and response bodies are captured
+	// Defaults to BodyCaptureDefault
+	BodyCapture BodyCapture
+	// MaxRequestBodySize overrides the maximum size of captured request body in bytes
+	// Defaults to TraceRequestsOptions.MaxRequestBodySize
+	MaxRequestBodySize int
+	// MaxResponseBodySize overrides the maximum size of captured response body in bytes
+	// Defaults to TraceRequestsOptions.MaxResponseBodySize
+	MaxResponseBodySize int
+	// SampleRate overrides the fraction of new traces that are sampled, between 0 and 1, see Rate.
+	// Use exclusions to stop tracing the route altogether
+	// Defaults to TraceRequestsOptions.SampleRate
+	SampleRate *float64
+}
+
+// matches tells whether the route applies to the request
+func (route *RouteOptions) matches(r *http.Request) bool {
+	return matchMethod(route.Methods, r.Method) &&
+		matchPath(r.URL.Path, route.Paths, route.Prefixes, route.Patterns)
+}
+
+// matchPath tells whether url path matches any of glob patterns, prefixes or regular expressions
+func matchPath(urlPath string, globs []string, prefixes []string, patterns []*regexp.Regexp) bool {
+	for _, glob := range globs {
+		if ok, err := path.Match(glob, urlPath); ok && err == nil {
+			return true
+		}
+	}
+
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(urlPath, prefix) {
+			return true
+		}
+	}
+
+	for _, pattern := range patterns {
+		if pattern.MatchString(urlPath) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// matchMethod tells whether the method is listed, empty list matches any method
+func matchMethod(methods []string, method string) bool {
+	if len(methods) == 0 {
+		return true
+	}
+
+	for _, candidate := range methods {
+		if strings.EqualFold(candidate, method) {
+			return true
+		}
+	}
+
+	return false
+}
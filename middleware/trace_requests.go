@@ -1,33 +1,93 @@
 package middleware
 
 import (
-	"bytes"
 	"fmt"
-	"io/ioutil"
 	"log"
+	"math/rand"
 	"net/http"
+	"regexp"
+	"runtime/debug"
 	"strconv"
 	"strings"
 
 	"github.com/Vinelab/tracing-go"
 	"github.com/Vinelab/tracing-go/formats"
-	"github.com/Vinelab/tracing-go/support/slice"
+	"github.com/Vinelab/tracing-go/redact"
 	"github.com/go-chi/chi/middleware"
 )
 
+const (
+	// DefaultMaxBodySize controls the default maximum size of captured request and response bodies in bytes
+	DefaultMaxBodySize = 64 * 1024
+)
+
 // TraceRequests middleware
 type TraceRequests struct {
-	tracer       tracing.Tracer
-	contentTypes []string
-	excludedPaths []string
+	tracer tracing.Tracer
+	opt    TraceRequestsOptions
+}
+
+// TraceRequestsOptions is a configuration container to setup the TraceRequests middleware.
+type TraceRequestsOptions struct {
+	// ContentTypes whitelists content-types of request and response bodies you want to log.
+	// Parameters such as charset are ignored when matching
+	ContentTypes []string
+	// ExcludedPaths lists url paths that are not traced. Paths may contain glob patterns
+	// as understood by path.Match, e.g. "/health/*". Query-string is never matched.
+	ExcludedPaths []string
+	// ExcludedPrefixes lists url path prefixes that are not traced, e.g. "/static/"
+	ExcludedPrefixes []string
+	// ExcludedPatterns lists regular expressions matched against url path of requests that are not traced
+	ExcludedPatterns []*regexp.Regexp
+	// Methods lists http methods that are traced
+	// Defaults to all methods
+	Methods []string
+	// Skip is a custom predicate, requests it returns true for are not traced
+	Skip func(r *http.Request) bool
+	// Routes overrides body capture and sampling for particular routes. The first matching route wins
+	Routes []RouteOptions
+	// SampleRate is the fraction of new traces that are sampled, between 0 and 1, see Rate.
+	// Requests continuing a trace keep the sampling decision of the caller. Unsampled requests
+	// are still traced and propagated, yet their spans are not reported.
+	// Defaults to the sampler of the tracer
+	SampleRate *float64
+	// MaxRequestBodySize controls the maximum size of captured request body in bytes.
+	// The rest of the body is replaced with truncation marker. Negative value disables the capture.
+	// Defaults to DefaultMaxBodySize
+	MaxRequestBodySize int
+	// MaxResponseBodySize controls the maximum size of captured response body in bytes.
+	// The rest of the body is replaced with truncation marker. Negative value disables the capture.
+	// Defaults to DefaultMaxBodySize
+	MaxResponseBodySize int
+	// Redaction scrubs headers, query-string and bodies before they are stored in span tags
+	// Defaults to a policy that masks redact.DefaultDeniedHeaders
+	Redaction *redact.Policy
+	// RecoveryHandler handles panics raised by the next handler after the panic is recorded on the span.
+	// The panic is re-raised when the handler is not set
+	RecoveryHandler func(w http.ResponseWriter, r *http.Request, recovered interface{})
+	// TraceIDHeader is the response header the trace ID is written to, e.g. "X-Trace-Id"
+	// Defaults to none
+	TraceIDHeader string
+	// UUIDHeader is the response header the Tracer.UUID() is written to, e.g. "X-Request-Id"
+	// Defaults to none
+	UUIDHeader string
+	// TraceResponse enables W3C traceresponse header in responses
+	// Defaults to false
+	TraceResponse bool
 }
 
 // NewTraceRequests creates a new TraceRequests middleware with the provided options
-func NewTraceRequests(tracer tracing.Tracer, contentTypes []string, excludedPaths []string) *TraceRequests {
+func NewTraceRequests(tracer tracing.Tracer, opt TraceRequestsOptions) *TraceRequests {
+	if opt.Redaction == nil {
+		opt.Redaction = redact.NewPolicy(redact.PolicyOptions{})
+	}
+
+	opt.MaxRequestBodySize = withDefault(opt.MaxRequestBodySize, DefaultMaxBodySize)
+	opt.MaxResponseBodySize = withDefault(opt.MaxResponseBodySize, DefaultMaxBodySize)
+
 	return &TraceRequests{
-		tracer:       tracer,
-		contentTypes: contentTypes,
-		excludedPaths: excludedPaths,
+		tracer: tracer,
+		opt:    opt,
 	}
 }
 
@@ -36,17 +96,12 @@ func NewTraceRequests(tracer tracing.Tracer, contentTypes []string, excludedPath
 // client ip, input, response code and content etc.
 func (mdlw *TraceRequests) Handler(next http.Handler) http.Handler {
 	fn := func(w http.ResponseWriter, r *http.Request) {
-		if slice.Contains(mdlw.excludedPaths, r.URL.Path) {
+		if mdlw.excluded(r) {
 			next.ServeHTTP(w, r)
 			return
 		}
 
-		// Create a proxy that hooks into response and allows us to access its contents
-		response := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
-
-		// Save response body in the buffer for logging purposes
-		buffer := bytes.Buffer{}
-		response.Tee(&buffer)
+		route := mdlw.route(r)
 
 		// Extract existing trace from request headers (if present)
 		spanContext, err := mdlw.tracer.Extract(r, formats.HTTP)
@@ -55,52 +110,176 @@ func (mdlw *TraceRequests) Handler(next http.Handler) http.Handler {
 		}
 
 		// Start the global span, it'll wrap the request lifecycle.
-		// span := tr.tracer.StartSpan("HTTP Request", spanContext)
-		span := mdlw.tracer.StartSpan("HTTP Request", spanContext)
+		span := mdlw.tracer.StartSpan("HTTP Request", spanContext, mdlw.sampling(route, spanContext)...)
+
+		// Bodies of requests that are not going to be reported are not worth buffering
+		maxRequestBodySize, maxResponseBodySize := -1, -1
+		if span.Context().IsSampled() {
+			maxRequestBodySize, maxResponseBodySize = mdlw.bodySizes(route)
+		}
+		anyContentType := route != nil && route.BodyCapture == BodyCaptureEnabled
+
+		// Save response body in the buffer for logging purposes. Streamed responses
+		// and responses with content-types that are not whitelisted are skipped.
+		var responseContent *responseCapture
+		if maxResponseBodySize >= 0 {
+			responseContent = &responseCapture{
+				bodyCapture:    newBodyCapture(maxResponseBodySize),
+				header:         w.Header(),
+				contentTypes:   mdlw.opt.ContentTypes,
+				anyContentType: anyContentType,
+			}
+			w = notifyFlush(w, responseContent.flushed)
+		}
+
+		// Create a proxy that hooks into response and allows us to access its contents
+		response := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+		if responseContent != nil {
+			response.Tee(responseContent)
+		}
+
+		// Record request body as the handler reads it instead of reading it upfront
+		var requestInput *bodyCapture
+		if maxRequestBodySize >= 0 && r.Body != nil && r.Body != http.NoBody &&
+			(anyContentType || matchContentType(mdlw.opt.ContentTypes, r.Header.Get("Content-Type"))) {
+			requestInput = newBodyCapture(maxRequestBodySize)
+			r.Body = &requestBody{ReadCloser: r.Body, capture: requestInput}
+		}
 
 		// Save request metadata for this span. Note that tags are searchable on UI.
 		span.Tag("type", "http")
 		span.Tag("request_method", r.Method)
 		span.Tag("request_path", r.URL.Path)
-		span.Tag("request_uri", r.RequestURI)
-		span.Tag("request_headers", getHeaders(r.Header))
+		span.Tag("request_uri", mdlw.opt.Redaction.URL(r.RequestURI))
+		span.Tag("request_headers", getHeaders(mdlw.opt.Redaction.Headers(r.Header)))
 		span.Tag("request_ip", strings.Split(r.RemoteAddr, ":")[0])
-		if slice.Contains(mdlw.contentTypes, r.Header.Get("Content-Type")) {
-			span.Tag("request_input", getRequestInput(r))
-		}
+
+		// Expose trace identifiers to the client before the handler writes the response
+		mdlw.writeTraceHeaders(response.Header(), span)
 
 		defer func() {
-			span.Tag("response_status", strconv.Itoa(response.Status()))
-			span.Tag("response_headers", getHeaders(response.Header()))
+			// Record the panic on the span so that crashes are visible in traces
+			recovered := recover()
+			if recovered != nil {
+				span.Tag("error", "true")
+				span.Tag("panic", fmt.Sprint(recovered))
+				span.Tag("panic_stack", string(debug.Stack()))
+				span.Annotate("Panic")
+			}
+
+			recoverable := recovered != nil && recovered != http.ErrAbortHandler && mdlw.opt.RecoveryHandler != nil
+			if recoverable {
+				mdlw.opt.RecoveryHandler(response, r, recovered)
+			}
+
+			if requestInput != nil {
+				span.Tag("request_input", mdlw.opt.Redaction.Body(r.Header.Get("Content-Type"), requestInput.String()))
+			}
+
+			status := response.Status()
+			if recovered != nil && (!recoverable || status == 0) {
+				status = http.StatusInternalServerError
+			}
+			span.Tag("response_status", strconv.Itoa(status))
+			span.Tag("response_headers", getHeaders(mdlw.opt.Redaction.Headers(response.Header())))
 
-			if slice.Contains(mdlw.contentTypes, response.Header().Get("Content-Type")) {
-				span.Tag("response_content", buffer.String())
+			if responseContent != nil && responseContent.captured() {
+				span.Tag("response_content", mdlw.opt.Redaction.Body(responseContent.contentType, responseContent.String()))
 			}
 
 			span.Finish()
 			mdlw.tracer.Flush()
+
+			if recovered != nil && !recoverable {
+				panic(recovered)
+			}
 		}()
 
-		next.ServeHTTP(response, r)
+		// Make the span available to handlers and instrumented clients down the chain
+		next.ServeHTTP(response, r.WithContext(tracing.ContextWithSpan(r.Context(), span)))
 	}
 
 	return http.HandlerFunc(fn)
 }
 
-func getRequestInput(r *http.Request) string {
-	data, err := ioutil.ReadAll(r.Body)
-	if err != nil {
-		log.Fatalf("unable to read request body %v", err)
+// writeTraceHeaders writes identifiers of the span into configured response headers
+func (mdlw *TraceRequests) writeTraceHeaders(header http.Header, span tracing.Span) {
+	ids := tracing.SpanIdentifiers(span, mdlw.tracer)
+
+	if mdlw.opt.TraceIDHeader != "" && ids.TraceID != "" {
+		header.Set(mdlw.opt.TraceIDHeader, ids.TraceID)
+	}
+
+	if mdlw.opt.UUIDHeader != "" && ids.UUID != "" {
+		header.Set(mdlw.opt.UUIDHeader, ids.UUID)
+	}
+
+	if mdlw.opt.TraceResponse && ids.TraceID != "" && ids.SpanID != "" {
+		header.Set("traceresponse", traceResponse(ids))
+	}
+}
+
+// excluded tells whether the request should not be traced at all
+func (mdlw *TraceRequests) excluded(r *http.Request) bool {
+	if !matchMethod(mdlw.opt.Methods, r.Method) {
+		return true
 	}
 
-	if err := r.Body.Close(); err != nil {
-		log.Fatal(err)
+	if matchPath(r.URL.Path, mdlw.opt.ExcludedPaths, mdlw.opt.ExcludedPrefixes, mdlw.opt.ExcludedPatterns) {
+		return true
 	}
 
-	// construct a new ReadCloser to hand it over to the next handler
-	r.Body = ioutil.NopCloser(bytes.NewReader(data))
+	return mdlw.opt.Skip != nil && mdlw.opt.Skip(r)
+}
+
+// route finds the first route that matches the request
+func (mdlw *TraceRequests) route(r *http.Request) *RouteOptions {
+	for i := range mdlw.opt.Routes {
+		if mdlw.opt.Routes[i].matches(r) {
+			return &mdlw.opt.Routes[i]
+		}
+	}
+
+	return nil
+}
+
+// sampling makes a sampling decision for the request that starts a new trace.
+// Requests continuing a trace are left to the decision of the caller.
+func (mdlw *TraceRequests) sampling(route *RouteOptions, parent tracing.SpanContext) []tracing.StartSpanOption {
+	rate := mdlw.opt.SampleRate
+	if route != nil && route.SampleRate != nil {
+		rate = route.SampleRate
+	}
+
+	if rate == nil || (parent != nil && parent.IsValid()) {
+		return nil
+	}
 
-	return string(data)
+	return []tracing.StartSpanOption{tracing.Sampled(*rate >= 1 || rand.Float64() < *rate)}
+}
+
+// bodySizes resolves maximum sizes of captured bodies, negative size disables the capture
+func (mdlw *TraceRequests) bodySizes(route *RouteOptions) (int, int) {
+	if route == nil {
+		return mdlw.opt.MaxRequestBodySize, mdlw.opt.MaxResponseBodySize
+	}
+
+	if route.BodyCapture == BodyCaptureDisabled {
+		return -1, -1
+	}
+
+	return withDefault(route.MaxRequestBodySize, mdlw.opt.MaxRequestBodySize),
+		withDefault(route.MaxResponseBodySize, mdlw.opt.MaxResponseBodySize)
+}
+
+// traceResponse encodes identifiers according to W3C Trace Context traceresponse header format
+func traceResponse(ids tracing.Identifiers) string {
+	flags := "00"
+	if ids.Sampled {
+		flags = "01"
+	}
+
+	return fmt.Sprintf("00-%032s-%016s-%s", ids.TraceID, ids.SpanID, flags)
 }
 
 func getHeaders(h http.Header) string {
@@ -110,3 +289,16 @@ func getHeaders(h http.Header) string {
 	}
 	return str
 }
+
+func withDefault(value int, fallback int) int {
+	if value == 0 {
+		return fallback
+	}
+
+	return value
+}
+
+// Rate returns a pointer to the sampling rate, e.g. SampleRate: middleware.Rate(0.1)
+func Rate(rate float64) *float64 {
+	return &rate
+}
@@ -0,0 +1,412 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"github.com/Vinelab/tracing-go/drivers/zipkin"
+	"github.com/openzipkin/zipkin-go/model"
+	"github.com/openzipkin/zipkin-go/reporter/recorder"
+)
+
+func ok(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusOK)
+}
+
+func TestZeroRateLeavesNewTracesUnsampled(t *testing.T) {
+	spans := record(t, TraceRequestsOptions{SampleRate: Rate(0)}, nil, ok)
+	if len(spans) != 0 {
+		t.Errorf("expected no reported spans, got %d", len(spans))
+	}
+}
+
+func TestZeroRateHonoursSampledParent(t *testing.T) {
+	header := http.Header{}
+	header.Set("X-B3-TraceId", "463ac35c9f6413ad")
+	header.Set("X-B3-SpanId", "72485a3953bb6124")
+	header.Set("X-B3-Sampled", "1")
+
+	spans := record(t, TraceRequestsOptions{SampleRate: Rate(0)}, header, ok)
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+
+	if spans[0].TraceID.String() != "463ac35c9f6413ad" {
+		t.Errorf("expected the trace to continue, got %s", spans[0].TraceID)
+	}
+}
+
+func TestFullRateHonoursUnsampledParent(t *testing.T) {
+	header := http.Header{}
+	header.Set("X-B3-TraceId", "463ac35c9f6413ad")
+	header.Set("X-B3-SpanId", "72485a3953bb6124")
+	header.Set("X-B3-Sampled", "0")
+
+	spans := record(t, TraceRequestsOptions{SampleRate: Rate(1)}, header, ok)
+	if len(spans) != 0 {
+		t.Errorf("expected no reported spans, got %d", len(spans))
+	}
+}
+
+func TestRouteRateOverridesDefault(t *testing.T) {
+	opt := TraceRequestsOptions{
+		SampleRate: Rate(1),
+		Routes:     []RouteOptions{{Prefixes: []string{"/"}, SampleRate: Rate(0)}},
+	}
+
+	if spans := record(t, opt, nil, ok); len(spans) != 0 {
+		t.Errorf("expected no reported spans, got %d", len(spans))
+	}
+}
+
+func TestUnsampledRequestPropagatesDecision(t *testing.T) {
+	opt := TraceRequestsOptions{SampleRate: Rate(0), TraceResponse: true}
+
+	var header string
+	record(t, opt, nil, func(w http.ResponseWriter, r *http.Request) {
+		header = w.Header().Get("traceresponse")
+	})
+
+	if len(header) != 55 || header[53:] != "00" {
+		t.Errorf("expected unsampled traceresponse, got %q", header)
+	}
+}
+
+func TestExclusions(t *testing.T) {
+	cases := map[string]struct {
+		opt    TraceRequestsOptions
+		req    request
+		traced bool
+	}{
+		"no exclusions": {
+			req:    request{target: "/users"},
+			traced: true,
+		},
+		"excluded path": {
+			opt: TraceRequestsOptions{ExcludedPaths: []string{"/health"}},
+			req: request{target: "/health"},
+		},
+		"excluded path ignores query-string": {
+			opt: TraceRequestsOptions{ExcludedPaths: []string{"/health"}},
+			req: request{target: "/health?verbose=1"},
+		},
+		"excluded glob": {
+			opt: TraceRequestsOptions{ExcludedPaths: []string{"/health/*"}},
+			req: request{target: "/health/db"},
+		},
+		"glob does not cross segments": {
+			opt:    TraceRequestsOptions{ExcludedPaths: []string{"/health/*"}},
+			req:    request{target: "/health/db/primary"},
+			traced: true,
+		},
+		"excluded prefix": {
+			opt: TraceRequestsOptions{ExcludedPrefixes: []string{"/static/"}},
+			req: request{target: "/static/css/app.css"},
+		},
+		"prefix mismatch": {
+			opt:    TraceRequestsOptions{ExcludedPrefixes: []string{"/static/"}},
+			req:    request{target: "/api/static/"},
+			traced: true,
+		},
+		"excluded pattern": {
+			opt: TraceRequestsOptions{ExcludedPatterns: []*regexp.Regexp{regexp.MustCompile(`^/v\d+/ping$`)}},
+			req: request{target: "/v2/ping"},
+		},
+		"pattern mismatch": {
+			opt:    TraceRequestsOptions{ExcludedPatterns: []*regexp.Regexp{regexp.MustCompile(`^/v\d+/ping$`)}},
+			req:    request{target: "/v2/ping/now"},
+			traced: true,
+		},
+		"traced method": {
+			opt:    TraceRequestsOptions{Methods: []string{"post", http.MethodGet}},
+			req:    request{method: http.MethodPost},
+			traced: true,
+		},
+		"untraced method": {
+			opt: TraceRequestsOptions{Methods: []string{http.MethodPost}},
+			req: request{method: http.MethodOptions},
+		},
+		"skipped": {
+			opt: TraceRequestsOptions{Skip: func(r *http.Request) bool { return r.Header.Get("X-Internal") != "" }},
+			req: request{header: http.Header{"X-Internal": {"1"}}},
+		},
+		"not skipped": {
+			opt:    TraceRequestsOptions{Skip: func(r *http.Request) bool { return r.Header.Get("X-Internal") != "" }},
+			traced: true,
+		},
+	}
+
+	for name, c := range cases {
+		served := false
+		spans, _, _ := do(t, c.opt, c.req, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			served = true
+			w.WriteHeader(http.StatusOK)
+		}))
+
+		if !served {
+			t.Errorf("%s: expected the request to be served", name)
+		}
+		if traced := len(spans) == 1; traced != c.traced {
+			t.Errorf("%s: expected traced %v, got %d spans", name, c.traced, len(spans))
+		}
+	}
+}
+
+func TestRouteBodyCapture(t *testing.T) {
+	cases := map[string]struct {
+		routes   []RouteOptions
+		req      request
+		input    string
+		response string
+	}{
+		"default": {
+			input:    "ping",
+			response: "pong",
+		},
+		"enabled for any content-type": {
+			routes:   []RouteOptions{{Paths: []string{"/upload"}, BodyCapture: BodyCaptureEnabled}},
+			req:      request{target: "/upload", header: http.Header{"Content-Type": {"application/octet-stream"}}},
+			input:    "ping",
+			response: "pong",
+		},
+		"disabled": {
+			routes: []RouteOptions{{Prefixes: []string{"/"}, BodyCapture: BodyCaptureDisabled}},
+		},
+		"overridden sizes": {
+			routes:   []RouteOptions{{Prefixes: []string{"/"}, MaxRequestBodySize: 2, MaxResponseBodySize: 3}},
+			input:    "pi... [truncated 2 bytes]",
+			response: "pon... [truncated 1 bytes]",
+		},
+		"route of another method": {
+			routes:   []RouteOptions{{Prefixes: []string{"/"}, Methods: []string{http.MethodPut}, BodyCapture: BodyCaptureDisabled}},
+			input:    "ping",
+			response: "pong",
+		},
+		"first matching route wins": {
+			routes: []RouteOptions{
+				{Patterns: []*regexp.Regexp{regexp.MustCompile(`^/$`)}, BodyCapture: BodyCaptureDisabled},
+				{Prefixes: []string{"/"}, BodyCapture: BodyCaptureEnabled},
+			},
+		},
+	}
+
+	for name, c := range cases {
+		c.req.method = http.MethodPost
+		c.req.body = "ping"
+		if c.req.header == nil {
+			c.req.header = http.Header{"Content-Type": {"text/plain"}}
+		}
+
+		opt := TraceRequestsOptions{ContentTypes: []string{"text/plain"}, Routes: c.routes}
+		contentType := c.req.header.Get("Content-Type")
+
+		spans, _, _ := do(t, opt, c.req, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = io.ReadAll(r.Body)
+			w.Header().Set("Content-Type", contentType)
+			_, _ = w.Write([]byte("pong"))
+		}))
+		if len(spans) != 1 {
+			t.Fatalf("%s: expected 1 span, got %d", name, len(spans))
+		}
+
+		if spans[0].Tags["request_input"] != c.input {
+			t.Errorf("%s: expected request_input %q, got %q", name, c.input, spans[0].Tags["request_input"])
+		}
+		if spans[0].Tags["response_content"] != c.response {
+			t.Errorf("%s: expected response_content %q, got %q", name, c.response, spans[0].Tags["response_content"])
+		}
+	}
+}
+
+func TestMatchPath(t *testing.T) {
+	cases := []struct {
+		path     string
+		expected bool
+	}{
+		{"/users/1", true},
+		{"/users", false},
+		{"/static/app.js", true},
+		{"/orders/42/items", true},
+		{"/orders/x/items", false},
+		{"/", false},
+	}
+
+	globs := []string{"/users/*", "[invalid"}
+	prefixes := []string{"/static/"}
+	patterns := []*regexp.Regexp{regexp.MustCompile(`^/orders/\d+/`)}
+
+	for _, c := range cases {
+		if got := matchPath(c.path, globs, prefixes, patterns); got != c.expected {
+			t.Errorf("%s: expected %v, got %v", c.path, c.expected, got)
+		}
+	}
+}
+
+func TestMatchMethod(t *testing.T) {
+	if !matchMethod(nil, http.MethodDelete) {
+		t.Error("expected empty list to match any method")
+	}
+	if !matchMethod([]string{"get"}, http.MethodGet) {
+		t.Error("expected methods matched case-insensitively")
+	}
+	if matchMethod([]string{http.MethodGet}, http.MethodPost) {
+		t.Error("expected unlisted method not to match")
+	}
+}
+
+// servePanic serves the request in-process and returns the value re-raised by the middleware, if any
+func servePanic(t *testing.T, opt TraceRequestsOptions, handler http.HandlerFunc) (model.SpanModel, *httptest.ResponseRecorder, interface{}) {
+	t.Helper()
+
+	rec := recorder.NewReporter()
+	tracer, err := zipkin.NewTracer(zipkin.TracerOptions{ServiceName: "middleware-test", Reporter: rec})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	w := httptest.NewRecorder()
+	var reraised interface{}
+	func() {
+		defer func() {
+			reraised = recover()
+		}()
+		NewTraceRequests(tracer, opt).Handler(handler).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	}()
+
+	spans := rec.Flush()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+
+	return spans[0], w, reraised
+}
+
+func TestPanicRecordedAndReraised(t *testing.T) {
+	span, _, reraised := servePanic(t, TraceRequestsOptions{}, func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	})
+
+	if reraised != "boom" {
+		t.Errorf("expected the panic to be re-raised, got %v", reraised)
+	}
+
+	expected := map[string]string{"error": "true", "panic": "boom", "response_status": "500"}
+	for key, value := range expected {
+		if span.Tags[key] != value {
+			t.Errorf("tag %s: expected %q, got %q", key, value, span.Tags[key])
+		}
+	}
+	if span.Tags["panic_stack"] == "" {
+		t.Error("expected panic_stack tag")
+	}
+
+	annotated := false
+	for _, annotation := range span.Annotations {
+		annotated = annotated || annotation.Value == "Panic"
+	}
+	if !annotated {
+		t.Errorf("expected Panic annotation, got %v", span.Annotations)
+	}
+}
+
+func TestRecoveryHandlerWritesResponse(t *testing.T) {
+	var handled interface{}
+	opt := TraceRequestsOptions{RecoveryHandler: func(w http.ResponseWriter, r *http.Request, recovered interface{}) {
+		handled = recovered
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}}
+
+	span, w, reraised := servePanic(t, opt, func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	})
+
+	if reraised != nil {
+		t.Errorf("expected the panic to be handled, got %v", reraised)
+	}
+	if handled != "boom" {
+		t.Errorf("expected recovery handler to receive the panic, got %v", handled)
+	}
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected status 503, got %d", w.Code)
+	}
+	if span.Tags["response_status"] != "503" || span.Tags["panic"] != "boom" {
+		t.Errorf("expected recovered response recorded, got %v", span.Tags)
+	}
+}
+
+func TestRecoveryHandlerWithoutResponse(t *testing.T) {
+	opt := TraceRequestsOptions{RecoveryHandler: func(w http.ResponseWriter, r *http.Request, recovered interface{}) {}}
+
+	span, _, reraised := servePanic(t, opt, func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	})
+
+	if reraised != nil {
+		t.Errorf("expected the panic to be handled, got %v", reraised)
+	}
+	if span.Tags["response_status"] != "500" {
+		t.Errorf("expected status 500 recorded, got %q", span.Tags["response_status"])
+	}
+}
+
+func TestAbortHandlerReraised(t *testing.T) {
+	called := false
+	opt := TraceRequestsOptions{RecoveryHandler: func(w http.ResponseWriter, r *http.Request, recovered interface{}) {
+		called = true
+	}}
+
+	span, _, reraised := servePanic(t, opt, func(w http.ResponseWriter, r *http.Request) {
+		panic(http.ErrAbortHandler)
+	})
+
+	if reraised != http.ErrAbortHandler {
+		t.Errorf("expected http.ErrAbortHandler re-raised, got %v", reraised)
+	}
+	if called {
+		t.Error("expected recovery handler not to handle http.ErrAbortHandler")
+	}
+	if span.Tags["error"] != "true" {
+		t.Errorf("expected error tag, got %q", span.Tags["error"])
+	}
+}
+
+func TestIdentifierHeadersSurviveEarlyWriteHeader(t *testing.T) {
+	opt := TraceRequestsOptions{TraceIDHeader: "X-Trace-Id", UUIDHeader: "X-Request-Id", TraceResponse: true}
+
+	spans, resp, _ := do(t, opt, request{}, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte("created"))
+	}))
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+
+	if resp.StatusCode != http.StatusCreated {
+		t.Errorf("expected status 201, got %d", resp.StatusCode)
+	}
+	if resp.Header.Get("X-Trace-Id") != spans[0].TraceID.String() {
+		t.Errorf("expected X-Trace-Id %s, got %q", spans[0].TraceID, resp.Header.Get("X-Trace-Id"))
+	}
+	if uuid := spans[0].Tags["uuid"]; uuid == "" || resp.Header.Get("X-Request-Id") != uuid {
+		t.Errorf("expected X-Request-Id %q, got %q", uuid, resp.Header.Get("X-Request-Id"))
+	}
+
+	// 64-bit trace IDs are left-padded to 32 hex characters
+	expected := "00-0000000000000000" + spans[0].TraceID.String() + "-" + spans[0].ID.String() + "-01"
+	if resp.Header.Get("traceresponse") != expected {
+		t.Errorf("expected traceresponse %s, got %q", expected, resp.Header.Get("traceresponse"))
+	}
+}
+
+func TestIdentifierHeadersDisabledByDefault(t *testing.T) {
+	_, resp, _ := do(t, TraceRequestsOptions{}, request{}, http.HandlerFunc(ok))
+
+	for _, header := range []string{"X-Trace-Id", "X-Request-Id", "traceresponse"} {
+		if value := resp.Header.Get(header); value != "" {
+			t.Errorf("expected no %s header, got %q", header, value)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package tracing
+
+import (
+	"time"
+)
+
+// StartSpanOptions holds configuration of a span that is about to start.
+// Drivers should use NewStartSpanOptions to resolve it from given options.
+type StartSpanOptions struct {
+	// StartTime overrides the start timestamp of the span
+	// Defaults to the current time
+	StartTime time.Time
+	// References relate the span to spans other than its parent
+	References []Reference
+	// Kind describes the role of the span in a remote interaction
+	// Defaults to none, meaning local operation
+	Kind SpanKind
+	// Detached spans are neither activated as the current span nor become the root span of the tracer
+	Detached bool
+	// Sampled is the sampling decision for a new trace, it never overrides the decision of the parent
+	// Defaults to the decision of the tracer's sampler
+	Sampled *bool
+}
+
+// SpanKind describes the role of the span in a remote interaction
+type SpanKind string
+
+const (
+	// SpanKindClient is a span of outgoing request awaiting the response, e.g. RPC call
+	SpanKindClient SpanKind = "client"
+	// SpanKindServer is a span of incoming request handled by the service
+	SpanKindServer SpanKind = "server"
+	// SpanKindProducer is a span of outgoing message nobody waits the response for
+	SpanKindProducer SpanKind = "producer"
+	// SpanKindConsumer is a span of incoming message processed by the service
+	SpanKindConsumer SpanKind = "consumer"
+)
+
+// ReferenceType describes how a span relates to the referenced span
+type ReferenceType string
+
+const (
+	// FollowsFromReference means the referenced span caused the span, but does not wait for its result
+	FollowsFromReference ReferenceType = "follows_from"
+	// LinkReference means the span is merely related to the referenced span, possibly from another trace
+	LinkReference ReferenceType = "link"
+)
+
+// Reference relates a span to another span context
+type Reference struct {
+	// Type describes the relationship
+	Type ReferenceType
+	// SpanContext is the context of the referenced span
+	SpanContext SpanContext
+	// Attributes describe the relationship, e.g. message id of a batch item
+	Attributes map[string]string
+}
+
+// StartSpanOption configures how a span is started, see Tracer.StartSpan
+type StartSpanOption func(opts *StartSpanOptions)
+
+// NewStartSpanOptions applies given options on top of the defaults
+func NewStartSpanOptions(opts ...StartSpanOption) StartSpanOptions {
+	options := StartSpanOptions{}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	return options
+}
+
+// StartTime sets an explicit start timestamp of the span. Use it to trace work
+// you only learn about afterwards, e.g. time a message spent in a queue
+func StartTime(timestamp time.Time) StartSpanOption {
+	return func(opts *StartSpanOptions) {
+		opts.StartTime = timestamp
+	}
+}
+
+// FollowsFrom relates the span to a span that caused it without waiting for its result,
+// e.g. asynchronous job scheduled by a request. Attributes are optional.
+//
+// Drivers without native support for follows-from references (i.e. Zipkin) continue the
+// trace of the referenced span unless explicit parent context is given.
+func FollowsFrom(spanCtx SpanContext, attributes map[string]string) StartSpanOption {
+	return func(opts *StartSpanOptions) {
+		opts.References = append(opts.References, Reference{
+			Type:        FollowsFromReference,
+			SpanContext: spanCtx,
+			Attributes:  attributes,
+		})
+	}
+}
+
+// Link relates the span to a span from the same or another trace, e.g. every message
+// of a batch processed at once. The option may be supplied multiple times. Attributes are optional.
+func Link(spanCtx SpanContext, attributes map[string]string) StartSpanOption {
+	return func(opts *StartSpanOptions) {
+		opts.References = append(opts.References, Reference{
+			Type:        LinkReference,
+			SpanContext: spanCtx,
+			Attributes:  attributes,
+		})
+	}
+}
+
+// Kind sets the role of the span in a remote interaction, e.g. SpanKindConsumer for the span
+// processing a message. Drivers without native support for span kinds may ignore it.
+func Kind(kind SpanKind) StartSpanOption {
+	return func(opts *StartSpanOptions) {
+		opts.Kind = kind
+	}
+}
+
+// Detached starts the span without activating it as the current span of the tracer. It neither becomes
+// the root span, so the span is not affected by Flush. Use it for work that may outlive the request,
+// e.g. goroutines (see Go).
+func Detached() StartSpanOption {
+	return func(opts *StartSpanOptions) {
+		opts.Detached = true
+	}
+}
+
+// Sampled records the sampling decision for a new trace instead of the one the tracer would make,
+// e.g. sampling rate configured per HTTP route. The decision is ignored when the span continues
+// a trace that was already sampled (or not) upstream. Unsampled spans are still started and
+// propagated, they are just never reported.
+func Sampled(sampled bool) StartSpanOption {
+	return func(opts *StartSpanOptions) {
+		opts.Sampled = &sampled
+	}
+}
@@ -0,0 +1,274 @@
+package redact
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+const (
+	// Mask is the default replacement for redacted values
+	Mask = "[REDACTED]"
+)
+
+var (
+	// DefaultDeniedHeaders lists headers that carry credentials and are masked by default
+	DefaultDeniedHeaders = []string{
+		"Authorization",
+		"Proxy-Authorization",
+		"Cookie",
+		"Set-Cookie",
+		"X-Api-Key",
+		"X-Auth-Token",
+	}
+)
+
+// Policy describes what should be scrubbed from captured data. It should be initialized using NewPolicy method.
+type Policy struct {
+	deniedHeaders  map[string]struct{}
+	allowedHeaders map[string]struct{}
+	jsonPaths      [][]string
+	jsonKeys       []*regexp.Regexp
+	patterns       []*regexp.Regexp
+	queryParams    map[string]struct{}
+	tags           map[string]struct{}
+	mask           string
+}
+
+// PolicyOptions is a configuration container to setup the Policy.
+type PolicyOptions struct {
+	// DeniedHeaders lists headers whose values are masked. Matching is case-insensitive
+	// Defaults to DefaultDeniedHeaders
+	DeniedHeaders []string
+	// AllowedHeaders lists the only headers whose values are kept, the rest are masked.
+	// Denied headers are masked even when allowed
+	// Defaults to all headers
+	AllowedHeaders []string
+	// JSONPaths lists dot-separated paths of JSON fields that are masked in bodies,
+	// e.g. "password" or "payment.card.number". Asterisk matches any key or array index.
+	JSONPaths []string
+	// Patterns lists regular expressions whose matches are masked in bodies and tag values
+	Patterns []*regexp.Regexp
+	// QueryParams lists query-string parameters whose values are masked in urls
+	QueryParams []string
+	// Tags lists span tag keys whose values are masked entirely
+	Tags []string
+	// Mask replaces redacted values
+	// Defaults to Mask
+	Mask string
+}
+
+// NewPolicy returns a new Policy
+func NewPolicy(opt PolicyOptions) *Policy {
+	deniedHeaders := opt.DeniedHeaders
+	if deniedHeaders == nil {
+		deniedHeaders = DefaultDeniedHeaders
+	}
+
+	mask := opt.Mask
+	if mask == "" {
+		mask = Mask
+	}
+
+	policy := &Policy{
+		deniedHeaders:  headerSet(deniedHeaders),
+		allowedHeaders: headerSet(opt.AllowedHeaders),
+		patterns:       opt.Patterns,
+		queryParams:    set(opt.QueryParams),
+		tags:           set(opt.Tags),
+		mask:           mask,
+	}
+
+	for _, path := range opt.JSONPaths {
+		segments := strings.Split(path, ".")
+		policy.jsonPaths = append(policy.jsonPaths, segments)
+
+		// Used for bodies that are not valid JSON, e.g. truncated ones
+		leaf := segments[len(segments)-1]
+		if leaf != "*" {
+			policy.jsonKeys = append(policy.jsonKeys, regexp.MustCompile(
+				`("`+regexp.QuoteMeta(leaf)+`"\s*:\s*)("(?:[^"\\]|\\.)*"|[^,}\]\s]+)`,
+			))
+		}
+	}
+
+	return policy
+}
+
+// Headers returns a copy of headers with denied (or not allowed) values masked
+func (policy *Policy) Headers(header http.Header) http.Header {
+	redacted := make(http.Header, len(header))
+	for key, values := range header {
+		if !policy.headerVisible(key) {
+			values = []string{policy.mask}
+		}
+
+		redacted[key] = values
+	}
+
+	return redacted
+}
+
+// URL masks values of sensitive query-string parameters in a given url or request uri.
+// The order of parameters is preserved
+func (policy *Policy) URL(rawURL string) string {
+	if len(policy.queryParams) == 0 {
+		return rawURL
+	}
+
+	parts := strings.SplitN(rawURL, "?", 2)
+	if len(parts) < 2 {
+		return rawURL
+	}
+
+	return parts[0] + "?" + policy.Query(parts[1])
+}
+
+// Query masks values of sensitive parameters in a given raw query-string
+func (policy *Policy) Query(rawQuery string) string {
+	pairs := strings.Split(rawQuery, "&")
+	for i, pair := range pairs {
+		key := strings.SplitN(pair, "=", 2)[0]
+
+		name, err := url.QueryUnescape(key)
+		if err != nil {
+			name = key
+		}
+
+		if _, ok := policy.queryParams[name]; ok {
+			pairs[i] = key + "=" + policy.mask
+		}
+	}
+
+	return strings.Join(pairs, "&")
+}
+
+// Body scrubs JSON paths from JSON bodies and masks pattern matches in any body
+func (policy *Policy) Body(contentType string, body string) string {
+	if len(policy.jsonPaths) > 0 && strings.Contains(strings.ToLower(contentType), "json") {
+		body = policy.scrubJSON(body)
+	}
+
+	return policy.scrubPatterns(body)
+}
+
+// Tag returns a value that is safe to be stored in a span tag with given key
+func (policy *Policy) Tag(key string, value string) string {
+	if _, ok := policy.tags[key]; ok {
+		return policy.mask
+	}
+
+	return policy.scrubPatterns(value)
+}
+
+// Text masks pattern matches in free-form text such as span names and annotations
+func (policy *Policy) Text(value string) string {
+	return policy.scrubPatterns(value)
+}
+
+// Fields returns a copy of log fields where every value is scrubbed the same way as tag
+// values, and pattern matches in keys are masked
+func (policy *Policy) Fields(fields map[string]string) map[string]string {
+	redacted := make(map[string]string, len(fields))
+	for key, value := range fields {
+		redacted[policy.scrubPatterns(key)] = policy.Tag(key, value)
+	}
+
+	return redacted
+}
+
+func (policy *Policy) headerVisible(key string) bool {
+	key = http.CanonicalHeaderKey(key)
+
+	if _, ok := policy.deniedHeaders[key]; ok {
+		return false
+	}
+
+	if len(policy.allowedHeaders) == 0 {
+		return true
+	}
+
+	_, ok := policy.allowedHeaders[key]
+	return ok
+}
+
+func (policy *Policy) scrubPatterns(value string) string {
+	for _, pattern := range policy.patterns {
+		value = pattern.ReplaceAllString(value, policy.mask)
+	}
+
+	return value
+}
+
+func (policy *Policy) scrubJSON(body string) string {
+	decoder := json.NewDecoder(strings.NewReader(body))
+	decoder.UseNumber()
+
+	var document interface{}
+	if err := decoder.Decode(&document); err != nil {
+		// Body is not a valid JSON (i.e. it was truncated), mask matching keys instead
+		for _, key := range policy.jsonKeys {
+			body = key.ReplaceAllString(body, fmt.Sprintf(`${1}"%s"`, policy.mask))
+		}
+
+		return body
+	}
+
+	for _, path := range policy.jsonPaths {
+		document = policy.maskPath(document, path)
+	}
+
+	buffer := bytes.Buffer{}
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(document); err != nil {
+		return body
+	}
+
+	return strings.TrimSuffix(buffer.String(), "\n")
+}
+
+func (policy *Policy) maskPath(node interface{}, path []string) interface{} {
+	if len(path) == 0 {
+		return policy.mask
+	}
+
+	switch value := node.(type) {
+	case map[string]interface{}:
+		for key, child := range value {
+			if path[0] == "*" || path[0] == key {
+				value[key] = policy.maskPath(child, path[1:])
+			}
+		}
+	case []interface{}:
+		for i, child := range value {
+			if path[0] == "*" || path[0] == fmt.Sprint(i) {
+				value[i] = policy.maskPath(child, path[1:])
+			}
+		}
+	}
+
+	return node
+}
+
+func headerSet(headers []string) map[string]struct{} {
+	canonical := make([]string, 0, len(headers))
+	for _, header := range headers {
+		canonical = append(canonical, http.CanonicalHeaderKey(header))
+	}
+
+	return set(canonical)
+}
+
+func set(items []string) map[string]struct{} {
+	result := make(map[string]struct{}, len(items))
+	for _, item := range items {
+		result[item] = struct{}{}
+	}
+
+	return result
+}
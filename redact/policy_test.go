@@ -0,0 +1,152 @@
+package redact
+
+import (
+	"net/http"
+	"regexp"
+	"testing"
+)
+
+func TestHeadersMasksDefaultDenied(t *testing.T) {
+	policy := NewPolicy(PolicyOptions{})
+
+	redacted := policy.Headers(http.Header{
+		"Authorization": {"Bearer token"},
+		"Cookie":        {"session=1"},
+		"Accept":        {"application/json"},
+	})
+
+	if redacted.Get("Authorization") != Mask || redacted.Get("Cookie") != Mask {
+		t.Errorf("expected credentials masked, got %v", redacted)
+	}
+	if redacted.Get("Accept") != "application/json" {
+		t.Errorf("expected Accept kept, got %q", redacted.Get("Accept"))
+	}
+}
+
+func TestHeadersDoesNotModifyOriginal(t *testing.T) {
+	policy := NewPolicy(PolicyOptions{})
+	header := http.Header{"Authorization": {"Bearer token"}}
+
+	policy.Headers(header)
+
+	if header.Get("Authorization") != "Bearer token" {
+		t.Errorf("expected original header intact, got %q", header.Get("Authorization"))
+	}
+}
+
+func TestHeadersAllowList(t *testing.T) {
+	policy := NewPolicy(PolicyOptions{
+		DeniedHeaders:  []string{"x-secret"},
+		AllowedHeaders: []string{"content-type", "X-Secret"},
+		Mask:           "***",
+	})
+
+	redacted := policy.Headers(http.Header{
+		"Content-Type": {"text/plain"},
+		"User-Agent":   {"curl"},
+		"X-Secret":     {"value"},
+	})
+
+	expected := map[string]string{
+		"Content-Type": "text/plain",
+		"User-Agent":   "***",
+		"X-Secret":     "***",
+	}
+	for key, value := range expected {
+		if redacted.Get(key) != value {
+			t.Errorf("%s: expected %q, got %q", key, value, redacted.Get(key))
+		}
+	}
+}
+
+func TestURL(t *testing.T) {
+	policy := NewPolicy(PolicyOptions{QueryParams: []string{"token", "api key"}})
+
+	cases := map[string]string{
+		"/users":                            "/users",
+		"/users?page=2":                     "/users?page=2",
+		"/users?token=abc&page=2":           "/users?token=[REDACTED]&page=2",
+		"/users?page=2&token=abc&token=def": "/users?page=2&token=[REDACTED]&token=[REDACTED]",
+		"/users?api+key=abc&api%20key=def":  "/users?api+key=[REDACTED]&api%20key=[REDACTED]",
+		"/users?token":                      "/users?token=[REDACTED]",
+	}
+
+	for raw, expected := range cases {
+		if got := policy.URL(raw); got != expected {
+			t.Errorf("%s: expected %q, got %q", raw, expected, got)
+		}
+	}
+}
+
+func TestURLWithoutQueryParams(t *testing.T) {
+	policy := NewPolicy(PolicyOptions{})
+
+	if got := policy.URL("/users?token=abc"); got != "/users?token=abc" {
+		t.Errorf("expected url intact, got %q", got)
+	}
+}
+
+func TestBodyJSONPaths(t *testing.T) {
+	policy := NewPolicy(PolicyOptions{JSONPaths: []string{"password", "payment.card.number", "items.*.secret", "tokens.1"}})
+
+	cases := map[string]string{
+		`{"password":"hunter2","name":"john"}`:                 `{"name":"john","password":"[REDACTED]"}`,
+		`{"payment":{"card":{"number":4111,"cvc":"1"}}}`:       `{"payment":{"card":{"cvc":"1","number":"[REDACTED]"}}}`,
+		`{"items":[{"secret":"a","id":1},{"secret":"b"}]}`:     `{"items":[{"id":1,"secret":"[REDACTED]"},{"secret":"[REDACTED]"}]}`,
+		`{"tokens":["a","b","c"]}`:                             `{"tokens":["a","[REDACTED]","c"]}`,
+		`{"name":"<john & co>","amount":12345678901234567890}`: `{"amount":12345678901234567890,"name":"<john & co>"}`,
+	}
+
+	for body, expected := range cases {
+		if got := policy.Body("application/json; charset=utf-8", body); got != expected {
+			t.Errorf("%s: expected %s, got %s", body, expected, got)
+		}
+	}
+}
+
+func TestBodyTruncatedJSONFallsBackToKeys(t *testing.T) {
+	policy := NewPolicy(PolicyOptions{JSONPaths: []string{"user.password", "pin", "items.*"}})
+
+	body := `{"user":{"password":"hun\"ter2","pin":1234,"name":"john"},"items":[1,2... [truncated 10 bytes]`
+	expected := `{"user":{"password":"[REDACTED]","pin":"[REDACTED]","name":"john"},"items":[1,2... [truncated 10 bytes]`
+
+	if got := policy.Body("application/json", body); got != expected {
+		t.Errorf("expected %s, got %s", expected, got)
+	}
+}
+
+func TestBodyNonJSONContentType(t *testing.T) {
+	policy := NewPolicy(PolicyOptions{
+		JSONPaths: []string{"password"},
+		Patterns:  []*regexp.Regexp{regexp.MustCompile(`\d{16}`)},
+	})
+
+	body := `password=hunter2&card=4111111111111111`
+	expected := `password=hunter2&card=[REDACTED]`
+
+	if got := policy.Body("application/x-www-form-urlencoded", body); got != expected {
+		t.Errorf("expected %s, got %s", expected, got)
+	}
+}
+
+func TestTagAndFields(t *testing.T) {
+	policy := NewPolicy(PolicyOptions{
+		Patterns: []*regexp.Regexp{regexp.MustCompile(`secret-\w+`)},
+		Tags:     []string{"password"},
+	})
+
+	if got := policy.Tag("password", "hunter2"); got != Mask {
+		t.Errorf("expected denied tag masked, got %q", got)
+	}
+	if got := policy.Tag("error", "failed with secret-value"); got != "failed with [REDACTED]" {
+		t.Errorf("expected pattern masked, got %q", got)
+	}
+
+	fields := policy.Fields(map[string]string{"password": "hunter2", "secret-key": "value"})
+	expected := map[string]string{"password": Mask, Mask: "value"}
+	for key, value := range expected {
+		if fields[key] != value {
+			t.Errorf("%s: expected %q, got %q", key, value, fields[key])
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package redact
+
+import (
+	"errors"
+	"time"
+
+	"github.com/Vinelab/tracing-go"
+)
+
+var (
+	// ErrBuilderUnsupported is returned when the wrapped tracer does not implement tracing.SpanContextBuilder
+	ErrBuilderUnsupported = errors.New("wrapped tracer cannot build span context")
+)
+
+// Tracer wraps any tracing.Tracer so that every tag value goes through the Policy
+// before it reaches the span. It should be initialized using NewTracer method.
+type Tracer struct {
+	tracing.Tracer
+	policy *Policy
+}
+
+// NewTracer returns a new Tracer that applies given policy on top of the wrapped tracer
+func NewTracer(tracer tracing.Tracer, policy *Policy) *Tracer {
+	return &Tracer{Tracer: tracer, policy: policy}
+}
+
+// StartSpan starts a new span with scrubbed name using the wrapped tracer
+func (tracer *Tracer) StartSpan(name string, spanCtx tracing.SpanContext, opts ...tracing.StartSpanOption) tracing.Span {
+	return tracer.wrap(tracer.Tracer.StartSpan(tracer.policy.Text(name), spanCtx, opts...))
+}
+
+// RootSpan retrieves the root span of the service
+func (tracer *Tracer) RootSpan() tracing.Span {
+	return tracer.wrap(tracer.Tracer.RootSpan())
+}
+
+// CurrentSpan retrieves the most recently activated span.
+func (tracer *Tracer) CurrentSpan() tracing.Span {
+	return tracer.wrap(tracer.Tracer.CurrentSpan())
+}
+
+// BuildSpanContext builds span context of the remote span using the wrapped tracer
+func (tracer *Tracer) BuildSpanContext(traceID string, spanID string, sampled bool) (tracing.SpanContext, error) {
+	builder, ok := tracer.Tracer.(tracing.SpanContextBuilder)
+	if !ok {
+		return nil, ErrBuilderUnsupported
+	}
+
+	return builder.BuildSpanContext(traceID, spanID, sampled)
+}
+
+func (tracer *Tracer) wrap(span tracing.Span) tracing.Span {
+	if span == nil {
+		return nil
+	}
+
+	if _, ok := span.(*Span); ok {
+		return span
+	}
+
+	return &Span{Span: span, policy: tracer.policy}
+}
+
+// Span scrubs tag values, log fields, annotations and the name of the wrapped span
+type Span struct {
+	tracing.Span
+	policy *Policy
+}
+
+// Tag give your span context for search, viewing and analysis. The value is
+// scrubbed according to the Policy before it is stored.
+func (span *Span) Tag(key string, value string) {
+	span.Span.Tag(key, span.policy.Tag(key, value))
+}
+
+// SetName sets (overrides) the string name for the logical operation this span represents.
+// Pattern matches are masked in the name.
+func (span *Span) SetName(name string) {
+	span.Span.SetName(span.policy.Text(name))
+}
+
+// Annotate associates an event that explains latency with a timestamp.
+// Pattern matches are masked in the message.
+func (span *Span) Annotate(message string) {
+	span.Span.Annotate(span.policy.Text(message))
+}
+
+// Log stores structured data. Fields are scrubbed the same way as tags.
+func (span *Span) Log(fields map[string]string) {
+	span.Span.Log(span.policy.Fields(fields))
+}
+
+// LogAt stores structured data with the given timestamp. Fields are scrubbed the same way as tags.
+func (span *Span) LogAt(timestamp time.Time, fields map[string]string) {
+	span.Span.LogAt(timestamp, span.policy.Fields(fields))
+}
@@ -0,0 +1,76 @@
+package redact
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Vinelab/tracing-go/drivers/zipkin"
+	"github.com/openzipkin/zipkin-go/reporter/recorder"
+)
+
+func newTestTracer(t *testing.T) (*Tracer, *recorder.ReporterRecorder) {
+	t.Helper()
+
+	rec := recorder.NewReporter()
+	wrapped, err := zipkin.NewTracer(zipkin.TracerOptions{ServiceName: "redact-test", Reporter: rec})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tracer := NewTracer(wrapped, NewPolicy(PolicyOptions{
+		Patterns: []*regexp.Regexp{regexp.MustCompile(`secret-\w+`)},
+		Tags:     []string{"password"},
+	}))
+
+	return tracer, rec
+}
+
+func TestStartSpanScrubsName(t *testing.T) {
+	tracer, rec := newTestTracer(t)
+
+	tracer.StartSpan("Request secret-name", tracer.EmptySpanContext()).Finish()
+
+	spans := rec.Flush()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+
+	if spans[0].Name != "Request [REDACTED]" {
+		t.Errorf("expected scrubbed name, got %q", spans[0].Name)
+	}
+}
+
+func TestSpanScrubsEverything(t *testing.T) {
+	tracer, rec := newTestTracer(t)
+
+	span := tracer.StartSpan("Request", tracer.EmptySpanContext())
+	span.SetName("Request secret-name")
+	span.Tag("token", "secret-tag")
+	span.Annotate("annotated secret-message")
+	span.Log(map[string]string{"password": "hunter2", "error": "failed with secret-log"})
+	span.LogAt(time.Now(), map[string]string{"secret-key": "value"})
+	span.Finish()
+
+	spans := rec.Flush()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+
+	recorded := spans[0]
+	leaks := []string{recorded.Name, recorded.Tags["token"]}
+	for _, annotation := range recorded.Annotations {
+		leaks = append(leaks, annotation.Value)
+	}
+
+	for _, value := range leaks {
+		if strings.Contains(value, "secret-") || strings.Contains(value, "hunter2") {
+			t.Errorf("value %q was not scrubbed", value)
+		}
+	}
+
+	if len(recorded.Annotations) != 3 {
+		t.Errorf("expected 3 annotations, got %d", len(recorded.Annotations))
+	}
+}
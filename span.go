@@ -1,5 +1,9 @@
 package tracing
 
+import (
+	"time"
+)
+
 // Span interface is returned by Tracer.StartSpan().
 // You can use it to provide your own custom implementation
 type Span interface {
@@ -14,13 +18,22 @@ type Span interface {
 	// timestamp from this, and set when appropriate.
 	Finish()
 
+	// FinishAt notifies that operation has finished at the given timestamp.
+	FinishAt(timestamp time.Time)
+
+	// FinishWithDuration notifies that operation has finished after the given duration since the start.
+	FinishWithDuration(duration time.Duration)
+
 	// Annotate associates an event that explains latency with a timestamp.
 	Annotate(message string)
 
-	// Log stores structured data. Despite this functionality being outlined in
-	// OpenTracing spec it's currently only supported in Jaeger
+	// Log stores structured data. Drivers without native support for
+	// logs (i.e. Zipkin) encode fields into a time-stamped annotation
 	Log(fields map[string]string)
 
+	// LogAt stores structured data with the given timestamp. Use it for backfilled events
+	LogAt(timestamp time.Time, fields map[string]string)
+
 	// IsRoot tells whether the span is a root span
 	IsRoot() bool
 
@@ -1,6 +1,37 @@
 package tracing
 
+// SpanContext carries identifiers of a span across process boundaries.
+// All identifiers are hex encoded and driver-neutral.
 type SpanContext interface {
 	// RawContext returns underlying (original) span context.
 	RawContext() interface{}
+
+	// TraceID returns identifier of the trace or empty string if there is none
+	TraceID() string
+
+	// SpanID returns identifier of the span or empty string if there is none
+	SpanID() string
+
+	// ParentSpanID returns identifier of the parent span or empty string if there is none
+	ParentSpanID() string
+
+	// IsSampled tells whether the trace is going to be reported
+	IsSampled() bool
+
+	// IsDebug tells whether the trace is forcibly sampled
+	IsDebug() bool
+
+	// IsValid tells whether the context carries a well-formed trace that can be continued
+	IsValid() bool
+
+	// IsEmpty tells whether the context carries nothing at all, as opposed to an invalid context
+	// extracted from a carrier with malformed headers. See Tracer.EmptySpanContext
+	IsEmpty() bool
+}
+
+// SpanContextBuilder is implemented by tracers able to build span context of a remote span
+// out of hex encoded identifiers, e.g. those received from another tracing library
+type SpanContextBuilder interface {
+	// BuildSpanContext returns span context of the remote span, error if identifiers are malformed
+	BuildSpanContext(traceID string, spanID string, sampled bool) (SpanContext, error)
 }
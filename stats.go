@@ -0,0 +1,25 @@
+package tracing
+
+import (
+	"time"
+)
+
+// Stats holds self-telemetry of the tracer. Use it to alert when spans silently stop reaching the backend
+type Stats struct {
+	// SpansStarted is the number of spans started by the tracer
+	SpansStarted uint64 `json:"spans_started"`
+	// SpansFinished is the number of spans finished
+	SpansFinished uint64 `json:"spans_finished"`
+	// SpansReported is the number of spans delivered to the backend
+	SpansReported uint64 `json:"spans_reported"`
+	// SpansDropped is the number of spans that were lost on the way to the backend
+	SpansDropped uint64 `json:"spans_dropped"`
+	// QueueDepth is the number of finished spans waiting to be reported
+	QueueDepth int `json:"queue_depth"`
+	// LastExportError describes the last failure to report spans, if any
+	LastExportError string `json:"last_export_error"`
+	// LastExportErrorAt is the time of the last failure to report spans
+	LastExportErrorAt time.Time `json:"last_export_error_at"`
+	// ExportLatency is the duration of the last attempt to report spans
+	ExportLatency time.Duration `json:"export_latency"`
+}
@@ -1,12 +0,0 @@
-package slice
-
-// Contains tells whether given string value exists in a slice
-func Contains(slice []string, item string) bool {
-	set := make(map[string]struct{}, len(slice))
-	for _, s := range slice {
-		set[s] = struct{}{}
-	}
-
-	_, ok := set[item]
-	return ok
-}
@@ -0,0 +1,121 @@
+package stack
+
+import (
+	"sync"
+
+	"github.com/Vinelab/tracing-go"
+)
+
+// Spans keeps a separate stack of active (unfinished) spans for every trace, so that concurrent
+// requests sharing a tracer neither see nor deactivate spans of each other. It is safe for concurrent use.
+// Its zero value is an empty stack ready to use.
+type Spans struct {
+	mu     sync.Mutex
+	traces map[string][]tracing.Span
+	// recent lists traces from the least to the most recently activated
+	recent []string
+}
+
+// Push activates the span within its trace
+func (stack *Spans) Push(span tracing.Span) {
+	traceID := traceOf(span)
+
+	stack.mu.Lock()
+	defer stack.mu.Unlock()
+
+	if stack.traces == nil {
+		stack.traces = make(map[string][]tracing.Span)
+	}
+
+	stack.traces[traceID] = append(stack.traces[traceID], span)
+	stack.forget(traceID)
+	stack.recent = append(stack.recent, traceID)
+}
+
+// Remove deactivates the span wherever it is in the stack of its trace, so that
+// spans finished out of order do not leave dead spans behind
+func (stack *Spans) Remove(span tracing.Span) {
+	traceID := traceOf(span)
+
+	stack.mu.Lock()
+	defer stack.mu.Unlock()
+
+	spans := stack.traces[traceID]
+	for i := len(spans) - 1; i >= 0; i-- {
+		if spans[i] == span {
+			spans = append(spans[:i], spans[i+1:]...)
+			break
+		}
+	}
+
+	if len(spans) > 0 {
+		stack.traces[traceID] = spans
+		return
+	}
+
+	delete(stack.traces, traceID)
+	stack.forget(traceID)
+}
+
+// Top retrieves the innermost active span of the most recently activated trace or nil if there is none
+func (stack *Spans) Top() tracing.Span {
+	stack.mu.Lock()
+	defer stack.mu.Unlock()
+
+	if len(stack.recent) == 0 {
+		return nil
+	}
+
+	spans := stack.traces[stack.recent[len(stack.recent)-1]]
+	return spans[len(spans)-1]
+}
+
+// TopOf retrieves the innermost active span of the given trace or nil if there is none
+func (stack *Spans) TopOf(traceID string) tracing.Span {
+	stack.mu.Lock()
+	defer stack.mu.Unlock()
+
+	spans := stack.traces[traceID]
+	if len(spans) == 0 {
+		return nil
+	}
+
+	return spans[len(spans)-1]
+}
+
+// Reset deactivates all spans of all traces
+func (stack *Spans) Reset() {
+	stack.mu.Lock()
+	defer stack.mu.Unlock()
+
+	stack.traces = nil
+	stack.recent = nil
+}
+
+// ResetTrace deactivates all spans of the given trace, leaving other traces intact
+func (stack *Spans) ResetTrace(traceID string) {
+	stack.mu.Lock()
+	defer stack.mu.Unlock()
+
+	delete(stack.traces, traceID)
+	stack.forget(traceID)
+}
+
+// forget removes the trace from the recency list, the caller must hold the lock
+func (stack *Spans) forget(traceID string) {
+	for i := len(stack.recent) - 1; i >= 0; i-- {
+		if stack.recent[i] == traceID {
+			stack.recent = append(stack.recent[:i], stack.recent[i+1:]...)
+			return
+		}
+	}
+}
+
+// traceOf resolves the key of the stack the span belongs to
+func traceOf(span tracing.Span) string {
+	if spanCtx := span.Context(); spanCtx != nil {
+		return spanCtx.TraceID()
+	}
+
+	return ""
+}
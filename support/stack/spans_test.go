@@ -0,0 +1,60 @@
+package stack
+
+import (
+	"testing"
+
+	"github.com/Vinelab/tracing-go"
+)
+
+type fakeContext struct {
+	tracing.SpanContext
+	traceID string
+}
+
+func (ctx fakeContext) TraceID() string {
+	return ctx.traceID
+}
+
+type fakeSpan struct {
+	tracing.Span
+	traceID string
+}
+
+func (span *fakeSpan) Context() tracing.SpanContext {
+	return fakeContext{traceID: span.traceID}
+}
+
+func TestTracesDoNotInterfere(t *testing.T) {
+	var stack Spans
+
+	requestA := &fakeSpan{traceID: "a"}
+	childA := &fakeSpan{traceID: "a"}
+	requestB := &fakeSpan{traceID: "b"}
+
+	stack.Push(requestA)
+	stack.Push(requestB)
+	stack.Push(childA)
+
+	if stack.Top() != childA {
+		t.Error("expected the most recently activated span on top")
+	}
+
+	stack.Remove(childA)
+	if stack.TopOf("a") != requestA {
+		t.Error("expected the parent to be restored within its trace")
+	}
+
+	stack.ResetTrace("a")
+	if stack.TopOf("a") != nil {
+		t.Error("expected trace a to be reset")
+	}
+
+	if stack.Top() != requestB {
+		t.Error("expected trace b to remain active")
+	}
+
+	stack.Remove(requestB)
+	if stack.Top() != nil {
+		t.Error("expected empty stack")
+	}
+}
@@ -9,12 +9,15 @@ type Tracer interface {
 	//
 	// If parent context does not contain a trace, a new trace will be implicitly created.
 	// Use EmptySpanContext to supply empty (nil) context.
-	StartSpan(name string, spanCtx SpanContext) Span
+	//
+	// Options such as StartTime may be supplied to customize the span.
+	StartSpan(name string, spanCtx SpanContext, opts ...StartSpanOption) Span
 
 	// RootSpan retrieves the root span of the service
 	RootSpan() Span
 
-	// CurrentSpan retrieves the most recently activated span.
+	// CurrentSpan retrieves the innermost span that has not finished yet. Finishing
+	// the span restores its parent as the current span.
 	CurrentSpan() Span
 
 	// UUID retrieves unique identifier associated with a root span
@@ -41,6 +44,9 @@ type Tracer interface {
 	// RegisterInjectionFormat register injector implementation for given format string
 	RegisterInjectionFormat(format string, injector Injector)
 
+	// Stats retrieves self-telemetry of the tracer and its reporter
+	Stats() Stats
+
 	// Flush may flush any pending spans to the transport and reset the state of the tracer.
 	// Make sure this method is always called after the request is finished.
 	Flush()